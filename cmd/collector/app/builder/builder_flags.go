@@ -27,6 +27,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/uber/jaeger/cmd/collector/app"
+	zs "github.com/uber/jaeger/cmd/collector/app/sanitizer/zipkin"
 )
 
 const (
@@ -55,6 +56,8 @@ type CollectorOptions struct {
 	CollectorZipkinHTTPPort int
 	// CollectorHealthCheckHTTPPort is the port that the health check service listens in on for http requests
 	CollectorHealthCheckHTTPPort int
+	// Sanitizer configures the chain of sanitizers applied to Zipkin spans
+	Sanitizer zs.Options
 }
 
 // AddFlags adds flags for CollectorOptions
@@ -66,6 +69,7 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.Int(collectorHTTPPort, 14268, "The http port for the collector service")
 	flags.Int(collectorZipkinHTTPort, 0, "The http port for the Zipkin collector service e.g. 9411")
 	flags.Int(collectorHealthCheckHTTPPort, 14269, "The http port for the health check service")
+	zs.AddFlags(flags)
 }
 
 // InitFromViper initializes CollectorOptions with properties from viper
@@ -77,5 +81,6 @@ func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper) *CollectorOptions {
 	cOpts.CollectorHTTPPort = v.GetInt(collectorHTTPPort)
 	cOpts.CollectorZipkinHTTPPort = v.GetInt(collectorZipkinHTTPort)
 	cOpts.CollectorHealthCheckHTTPPort = v.GetInt(collectorHealthCheckHTTPPort)
+	cOpts.Sanitizer.InitFromViper(v)
 	return cOpts
 }
@@ -124,11 +124,7 @@ func (spanHb *SpanHandlerBuilder) BuildHandlers() (app.ZipkinSpansHandler, app.J
 	hostname, _ := os.Hostname()
 	hostMetrics := spanHb.metricsFactory.Namespace(hostname, nil)
 
-	zSanitizer := zs.NewChainedSanitizer(
-		zs.NewSpanDurationSanitizer(spanHb.logger),
-		zs.NewParentIDSanitizer(spanHb.logger),
-		zs.NewErrorTagSanitizer(),
-	)
+	zSanitizer := zs.NewSanitizerChainFromOptions(spanHb.collectorOpts.Sanitizer, spanHb.logger, spanHb.metricsFactory)
 
 	spanProcessor := app.NewSpanProcessor(
 		spanHb.spanWriter,
@@ -140,7 +136,7 @@ func (spanHb *SpanHandlerBuilder) BuildHandlers() (app.ZipkinSpansHandler, app.J
 		app.Options.QueueSize(spanHb.collectorOpts.QueueSize),
 	)
 
-	return app.NewZipkinSpanHandler(spanHb.logger, spanProcessor, zSanitizer),
+	return app.NewZipkinSpanHandler(spanHb.logger, spanProcessor, zSanitizer, spanHb.metricsFactory),
 		app.NewJaegerSpanHandler(spanHb.logger, spanProcessor)
 }
 
@@ -0,0 +1,65 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewAnnotationBoundsSanitizer returns a sanitizer that drops annotations whose timestamp falls outside
+// of the span, i.e. before its timestamp or after its timestamp plus duration. Annotations exactly on the
+// boundaries are kept. Spans without a timestamp or duration are left untouched.
+func NewAnnotationBoundsSanitizer(logger *zap.Logger) Sanitizer {
+	return &annotationBoundsSanitizer{log: spanLogger{logger}}
+}
+
+type annotationBoundsSanitizer struct {
+	log spanLogger
+}
+
+func (s *annotationBoundsSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Timestamp == nil || span.Duration == nil {
+		return span, nil
+	}
+	start, end := *span.Timestamp, *span.Timestamp+*span.Duration
+	var annos []*zc.Annotation
+	for i, anno := range span.Annotations {
+		if anno.Timestamp >= start && anno.Timestamp <= end {
+			if annos != nil {
+				annos = append(annos, anno)
+			}
+			continue
+		}
+		if annos == nil {
+			annos = make([]*zc.Annotation, i, len(span.Annotations)-1)
+			copy(annos, span.Annotations[:i])
+		}
+		s.log.ForSpan(span).Debug("Dropping annotation outside of the span",
+			zap.String("annotation", anno.Value),
+			zap.Int64("timestamp", anno.Timestamp))
+	}
+	if annos != nil {
+		span.Annotations = annos
+	}
+	return span, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestAnnotationBoundsSanitizer(t *testing.T) {
+	logger, logBuf := testutils.NewLogger()
+	sanitizer := NewAnnotationBoundsSanitizer(logger)
+	timestamp, duration := int64(100), int64(50)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Timestamp: &timestamp,
+		Duration:  &duration,
+		Annotations: []*zc.Annotation{
+			{Value: "before", Timestamp: 99},
+			{Value: "at-start", Timestamp: 100},
+			{Value: "inside", Timestamp: 120},
+			{Value: "at-end", Timestamp: 150},
+			{Value: "after", Timestamp: 151},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"at-start", "inside", "at-end"}, annotationValues(span))
+	assert.Contains(t, logBuf.String(), `"annotation":"before"`)
+	assert.Contains(t, logBuf.String(), `"annotation":"after"`)
+}
+
+func TestAnnotationBoundsSanitizerNoTimestamp(t *testing.T) {
+	logger, logBuf := testutils.NewLogger()
+	duration := int64(50)
+	span, err := NewAnnotationBoundsSanitizer(logger).Sanitize(&zc.Span{
+		Duration:    &duration,
+		Annotations: []*zc.Annotation{{Value: "before", Timestamp: 99}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"before"}, annotationValues(span))
+	assert.Empty(t, logBuf.String())
+}
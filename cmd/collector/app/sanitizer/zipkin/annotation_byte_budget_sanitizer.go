@@ -0,0 +1,95 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"sort"
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const annotationByteBudgetExceededTag = "warnAnnotationByteBudgetExceeded"
+
+// NewAnnotationByteBudgetSanitizer returns a sanitizer that limits the total size of the annotation values
+// of a span to maxBytes, by dropping the annotations with the largest values first. Ties are broken by dropping
+// the later annotation first, so the result is deterministic. Core annotations are always preserved.
+// The number of dropped annotations, if any, is recorded in a 'warnAnnotationByteBudgetExceeded' tag.
+func NewAnnotationByteBudgetSanitizer(maxBytes int) Sanitizer {
+	return &annotationByteBudgetSanitizer{maxBytes: maxBytes}
+}
+
+type annotationByteBudgetSanitizer struct {
+	maxBytes int
+}
+
+func (s *annotationByteBudgetSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	total := 0
+	for _, anno := range span.Annotations {
+		total += len(anno.Value)
+	}
+	if total <= s.maxBytes {
+		return span, nil
+	}
+	candidates := bySizeDesc{annotations: span.Annotations}
+	for i, anno := range span.Annotations {
+		if !isCoreAnnotation(anno) {
+			candidates.indices = append(candidates.indices, i)
+		}
+	}
+	sort.Sort(candidates)
+	drop := make(map[int]struct{})
+	for _, i := range candidates.indices {
+		if total <= s.maxBytes {
+			break
+		}
+		total -= len(span.Annotations[i].Value)
+		drop[i] = struct{}{}
+	}
+	if len(drop) == 0 {
+		return span, nil
+	}
+	annos := make([]*zc.Annotation, 0, len(span.Annotations)-len(drop))
+	for i, anno := range span.Annotations {
+		if _, ok := drop[i]; !ok {
+			annos = append(annos, anno)
+		}
+	}
+	span.Annotations = annos
+	appendStringTag(span, annotationByteBudgetExceededTag, strconv.Itoa(len(drop)))
+	return span, nil
+}
+
+// bySizeDesc sorts annotation indices by decreasing value size, and by decreasing index for equal sizes.
+type bySizeDesc struct {
+	annotations []*zc.Annotation
+	indices     []int
+}
+
+func (s bySizeDesc) Len() int      { return len(s.indices) }
+func (s bySizeDesc) Swap(i, j int) { s.indices[i], s.indices[j] = s.indices[j], s.indices[i] }
+func (s bySizeDesc) Less(i, j int) bool {
+	a, b := s.annotations[s.indices[i]], s.annotations[s.indices[j]]
+	if len(a.Value) != len(b.Value) {
+		return len(a.Value) > len(b.Value)
+	}
+	return s.indices[i] > s.indices[j]
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestAnnotationByteBudgetSanitizer(t *testing.T) {
+	span := &zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND},
+			{Value: "0123456789"},
+			{Value: "abcde"},
+			{Value: "ABCDE"},
+			{Value: "xyz"},
+			{Value: zc.CLIENT_RECV},
+		},
+	}
+	span, err := NewAnnotationByteBudgetSanitizer(12).Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.Annotation{
+		{Value: zc.CLIENT_SEND},
+		{Value: "abcde"},
+		{Value: "xyz"},
+		{Value: zc.CLIENT_RECV},
+	}, span.Annotations)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(annotationByteBudgetExceededTag, "2")}, span.BinaryAnnotations)
+}
+
+func TestAnnotationByteBudgetSanitizerPreservesCore(t *testing.T) {
+	span := &zc.Span{
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV}, {Value: "log"}, {Value: zc.SERVER_SEND}},
+	}
+	span, err := NewAnnotationByteBudgetSanitizer(1).Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.Annotation{{Value: zc.SERVER_RECV}, {Value: zc.SERVER_SEND}}, span.Annotations)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(annotationByteBudgetExceededTag, "1")}, span.BinaryAnnotations)
+}
+
+func TestAnnotationByteBudgetSanitizerOnlyCore(t *testing.T) {
+	span := &zc.Span{
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV}, {Value: zc.SERVER_SEND}},
+	}
+	span, err := NewAnnotationByteBudgetSanitizer(1).Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.Annotations, 2)
+	assert.Empty(t, span.BinaryAnnotations)
+}
+
+func TestAnnotationByteBudgetSanitizerUnderBudget(t *testing.T) {
+	span := &zc.Span{Annotations: []*zc.Annotation{{Value: "log"}}}
+	span, err := NewAnnotationByteBudgetSanitizer(3).Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.Annotations, 1)
+	assert.Empty(t, span.BinaryAnnotations)
+}
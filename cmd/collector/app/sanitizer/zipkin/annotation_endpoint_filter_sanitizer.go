@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const filteredAnnotationsByEndpointTag = "warnFilteredAnnotationsByEndpoint"
+
+// NewAnnotationEndpointFilterSanitizer returns a sanitizer that removes the annotations and binary annotations
+// whose host service name is denied, e.g. 'sa' annotations pointing to a noisy sidecar. Core annotations
+// are never removed. The number of removed annotations is recorded in a 'warnFilteredAnnotationsByEndpoint' tag.
+func NewAnnotationEndpointFilterSanitizer(deniedServices map[string]bool) Sanitizer {
+	return &annotationEndpointFilterSanitizer{denied: deniedServices}
+}
+
+type annotationEndpointFilterSanitizer struct {
+	denied map[string]bool
+}
+
+func (s *annotationEndpointFilterSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	dropped := 0
+	var annos []*zc.Annotation
+	for i, anno := range span.Annotations {
+		if !isCoreAnnotation(anno) && s.isDenied(anno.Host) {
+			if annos == nil {
+				annos = append(make([]*zc.Annotation, 0, len(span.Annotations)), span.Annotations[:i]...)
+			}
+			dropped++
+		} else if annos != nil {
+			annos = append(annos, anno)
+		}
+	}
+	if annos != nil {
+		span.Annotations = annos
+	}
+	var binAnnos []*zc.BinaryAnnotation
+	for i, binAnno := range span.BinaryAnnotations {
+		if s.isDenied(binAnno.Host) {
+			if binAnnos == nil {
+				binAnnos = append(make([]*zc.BinaryAnnotation, 0, len(span.BinaryAnnotations)), span.BinaryAnnotations[:i]...)
+			}
+			dropped++
+		} else if binAnnos != nil {
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	if binAnnos != nil {
+		span.BinaryAnnotations = binAnnos
+	}
+	if dropped > 0 {
+		appendStringTag(span, filteredAnnotationsByEndpointTag, strconv.Itoa(dropped))
+	}
+	return span, nil
+}
+
+func (s *annotationEndpointFilterSanitizer) isDenied(host *zc.Endpoint) bool {
+	return host != nil && s.denied[host.ServiceName]
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestAnnotationEndpointFilterSanitizer(t *testing.T) {
+	sidecar := &zc.Endpoint{ServiceName: "sidecar"}
+	app := &zc.Endpoint{ServiceName: "app"}
+	span := &zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.SERVER_RECV, Host: sidecar},
+			{Value: "retrying", Host: sidecar},
+			{Value: "cache miss", Host: app},
+			{Value: "no host"},
+		},
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: zc.SERVER_ADDR, Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL, Host: sidecar},
+			{Key: "component", Value: []byte("http"), AnnotationType: zc.AnnotationType_STRING, Host: app},
+		},
+	}
+	span, err := NewAnnotationEndpointFilterSanitizer(map[string]bool{"sidecar": true}).Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.Annotation{
+		{Value: zc.SERVER_RECV, Host: sidecar},
+		{Value: "cache miss", Host: app},
+		{Value: "no host"},
+	}, span.Annotations)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		{Key: "component", Value: []byte("http"), AnnotationType: zc.AnnotationType_STRING, Host: app},
+		stringTag(filteredAnnotationsByEndpointTag, "2"),
+	}, span.BinaryAnnotations)
+}
+
+func TestAnnotationEndpointFilterSanitizerNoop(t *testing.T) {
+	span := &zc.Span{
+		Annotations:       []*zc.Annotation{{Value: "event", Host: &zc.Endpoint{ServiceName: "app"}}},
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("component", "http")},
+	}
+	span, err := NewAnnotationEndpointFilterSanitizer(map[string]bool{"sidecar": true}).Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.Annotations, 1)
+	assert.Len(t, span.BinaryAnnotations, 1)
+}
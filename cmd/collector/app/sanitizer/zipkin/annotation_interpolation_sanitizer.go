@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const interpolatedAnnotationTimestampTag = "warnInterpolatedAnnotationTimestamp"
+
+// NewAnnotationTimestampInterpolationSanitizer returns a sanitizer that repairs annotations with a zero timestamp.
+// An annotation between two annotations with valid timestamps gets a timestamp interpolated linearly between
+// them, according to its position. Leading and trailing annotations get the span timestamp, if any.
+// The number of repaired annotations is recorded in a 'warnInterpolatedAnnotationTimestamp' tag.
+func NewAnnotationTimestampInterpolationSanitizer() Sanitizer {
+	return &annotationTimestampInterpolationSanitizer{}
+}
+
+type annotationTimestampInterpolationSanitizer struct {
+}
+
+func (s *annotationTimestampInterpolationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	repaired := 0
+	prev := -1
+	for i := 0; i <= len(span.Annotations); i++ {
+		if i < len(span.Annotations) && span.Annotations[i].Timestamp == 0 {
+			continue
+		}
+		if i-prev > 1 {
+			repaired += s.fill(span, prev, i)
+		}
+		prev = i
+	}
+	if repaired > 0 {
+		appendStringTag(span, interpolatedAnnotationTimestampTag, strconv.Itoa(repaired))
+	}
+	return span, nil
+}
+
+// fill sets the timestamps of the annotations strictly between indices prev and next, where prev may be -1
+// and next may be len(span.Annotations) for leading and trailing annotations. It returns the number of
+// annotations repaired.
+func (s *annotationTimestampInterpolationSanitizer) fill(span *zc.Span, prev, next int) int {
+	if prev < 0 || next == len(span.Annotations) {
+		if span.Timestamp == nil || *span.Timestamp == 0 {
+			return 0
+		}
+		for i := prev + 1; i < next; i++ {
+			span.Annotations[i].Timestamp = *span.Timestamp
+		}
+		return next - prev - 1
+	}
+	start, end := span.Annotations[prev].Timestamp, span.Annotations[next].Timestamp
+	for i := prev + 1; i < next; i++ {
+		span.Annotations[i].Timestamp = start + (end-start)*int64(i-prev)/int64(next-prev)
+	}
+	return next - prev - 1
+}
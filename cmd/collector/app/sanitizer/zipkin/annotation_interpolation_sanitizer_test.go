@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func annotationTimestamps(span *zc.Span) []int64 {
+	timestamps := make([]int64, len(span.Annotations))
+	for i, anno := range span.Annotations {
+		timestamps[i] = anno.Timestamp
+	}
+	return timestamps
+}
+
+func TestAnnotationTimestampInterpolationSanitizer(t *testing.T) {
+	timestamp := int64(50)
+	tests := []struct {
+		timestamp *int64
+		input     []int64
+		expected  []int64
+		repaired  string
+	}{
+		{input: []int64{100, 0, 200}, expected: []int64{100, 150, 200}, repaired: "1"},
+		{input: []int64{100, 0, 0, 400}, expected: []int64{100, 200, 300, 400}, repaired: "2"},
+		{timestamp: &timestamp, input: []int64{0, 100, 0}, expected: []int64{50, 100, 50}, repaired: "2"},
+		{input: []int64{0, 100, 0}, expected: []int64{0, 100, 0}},
+		{input: []int64{100, 200}, expected: []int64{100, 200}},
+	}
+	sanitizer := NewAnnotationTimestampInterpolationSanitizer()
+	for _, test := range tests {
+		span := &zc.Span{Timestamp: test.timestamp}
+		for _, ts := range test.input {
+			span.Annotations = append(span.Annotations, &zc.Annotation{Timestamp: ts})
+		}
+		span, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, annotationTimestamps(span))
+		if test.repaired == "" {
+			assert.Empty(t, span.BinaryAnnotations)
+		} else {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(interpolatedAnnotationTimestampTag, test.repaired)}, span.BinaryAnnotations)
+		}
+	}
+}
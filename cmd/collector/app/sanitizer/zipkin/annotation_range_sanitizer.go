@@ -0,0 +1,58 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const excessiveAnnotationRangeTag = "warnExcessiveAnnotationRange"
+
+// NewAnnotationRangeSanitizer returns a sanitizer that tags spans whose annotations are spread over more than
+// maxRange with 'warnExcessiveAnnotationRange', recording the range in microseconds. Such spans most likely
+// suffer from a unit or clock bug. Annotations without a timestamp are ignored, so spans with fewer than two
+// timestamped annotations are never tagged.
+func NewAnnotationRangeSanitizer(maxRange time.Duration, logger *zap.Logger) Sanitizer {
+	return &annotationRangeSanitizer{
+		maxRange: int64(maxRange / time.Microsecond),
+		log:      spanLogger{logger},
+	}
+}
+
+type annotationRangeSanitizer struct {
+	maxRange int64
+	log      spanLogger
+}
+
+func (s *annotationRangeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	min, max := annotationWindow(span)
+	if max-min <= s.maxRange {
+		return span, nil
+	}
+	s.log.ForSpan(span).Debug("Excessive annotation range", zap.Int64("range", max-min))
+	appendStringTag(span, excessiveAnnotationRangeTag, strconv.FormatInt(max-min, 10))
+	return span, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestAnnotationRangeSanitizer(t *testing.T) {
+	tests := []struct {
+		annotations []*zc.Annotation
+		tagged      bool
+	}{
+		{annotations: nil},
+		{annotations: []*zc.Annotation{{Timestamp: 1}}},
+		{annotations: []*zc.Annotation{{Timestamp: 5000}, {Timestamp: 1000}, {Timestamp: 1000 + 3600000000}}},
+		{annotations: []*zc.Annotation{{Timestamp: 5000}, {Timestamp: 1000}, {Timestamp: 1000 + 3600000001}}, tagged: true},
+		{annotations: []*zc.Annotation{{Timestamp: 0}, {Timestamp: 1500000000000000}, {Timestamp: 1500000000001000}}},
+		{annotations: []*zc.Annotation{{Timestamp: 0}, {Timestamp: 1500000000000000}}},
+	}
+	sanitizer := NewAnnotationRangeSanitizer(time.Hour, zap.NewNop())
+	for i, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{Annotations: test.annotations})
+		require.NoError(t, err)
+		if test.tagged {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(excessiveAnnotationRangeTag, "3600000001")}, span.BinaryAnnotations)
+		} else {
+			assert.Empty(t, span.BinaryAnnotations, "case %d", i)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"sort"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewAnnotationSortSanitizer returns a sanitizer that sorts the annotations of a span by timestamp.
+// The sort is stable, so annotations with equal timestamps keep their relative order. Spans whose
+// annotations are already sorted are left untouched.
+func NewAnnotationSortSanitizer() Sanitizer {
+	return &annotationSortSanitizer{}
+}
+
+type annotationSortSanitizer struct {
+}
+
+func (s *annotationSortSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	annos := byTimestamp(span.Annotations)
+	if !sort.IsSorted(annos) {
+		sort.Stable(annos)
+	}
+	return span, nil
+}
+
+// byTimestamp sorts annotations by increasing timestamp.
+type byTimestamp []*zc.Annotation
+
+func (s byTimestamp) Len() int           { return len(s) }
+func (s byTimestamp) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byTimestamp) Less(i, j int) bool { return s[i].Timestamp < s[j].Timestamp }
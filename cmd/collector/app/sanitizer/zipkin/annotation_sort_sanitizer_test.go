@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestAnnotationSortSanitizer(t *testing.T) {
+	tests := []struct {
+		timestamps []int64
+		values     []string
+		expected   []string
+	}{
+		{[]int64{100, 200, 300}, []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{[]int64{300, 200, 100}, []string{"a", "b", "c"}, []string{"c", "b", "a"}},
+		{[]int64{200, 100, 200, 100, 200}, []string{"a", "b", "c", "d", "e"}, []string{"b", "d", "a", "c", "e"}},
+		{[]int64{100, 100, 100}, []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{nil, nil, []string{}},
+	}
+	sanitizer := NewAnnotationSortSanitizer()
+	for _, test := range tests {
+		span := &zc.Span{}
+		for i, ts := range test.timestamps {
+			span.Annotations = append(span.Annotations, &zc.Annotation{Timestamp: ts, Value: test.values[i]})
+		}
+		span, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, annotationValues(span), "%v", test.timestamps)
+	}
+}
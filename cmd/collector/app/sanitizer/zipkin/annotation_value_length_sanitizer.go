@@ -0,0 +1,63 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const truncatedAnnotationValueTag = "warnTruncatedAnnotationValue"
+
+// NewAnnotationValueLengthSanitizer returns a sanitizer that truncates annotation values longer than maxBytes,
+// without splitting UTF-8 encoded characters, so the result may be a few bytes shorter than maxBytes.
+// Core annotations are exempt since their values carry semantics. The number of truncated annotations
+// is recorded in a 'warnTruncatedAnnotationValue' tag. A negative maxBytes disables the sanitizer.
+func NewAnnotationValueLengthSanitizer(maxBytes int, logger *zap.Logger) Sanitizer {
+	if maxBytes < 0 {
+		return NewNoopSanitizer()
+	}
+	return &annotationValueLengthSanitizer{maxBytes: maxBytes, log: spanLogger{logger}}
+}
+
+type annotationValueLengthSanitizer struct {
+	maxBytes int
+	log      spanLogger
+}
+
+func (s *annotationValueLengthSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	truncated := 0
+	for _, anno := range span.Annotations {
+		if len(anno.Value) <= s.maxBytes || isCoreAnnotation(anno) {
+			continue
+		}
+		s.log.ForSpan(span).Debug("Truncated annotation value", zap.Int("length", len(anno.Value)))
+		anno.Value = truncateUTF8(anno.Value, s.maxBytes)
+		truncated++
+	}
+	if truncated > 0 {
+		appendStringTag(span, truncatedAnnotationValueTag, strconv.Itoa(truncated))
+	}
+	return span, nil
+}
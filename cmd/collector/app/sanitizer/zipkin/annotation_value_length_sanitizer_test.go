@@ -0,0 +1,71 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestAnnotationValueLengthSanitizer(t *testing.T) {
+	tests := []struct {
+		input     []string
+		expected  []string
+		truncated string
+	}{
+		{input: []string{"abcdef", zc.CLIENT_SEND}, expected: []string{"abcdef", zc.CLIENT_SEND}},
+		{input: []string{"abcdefg", "abc"}, expected: []string{"abcdef", "abc"}, truncated: "1"},
+		{input: []string{"abcdé", "abcdeé"}, expected: []string{"abcdé", "abcde"}, truncated: "1"},
+		{input: []string{"日本語", "日本語x"}, expected: []string{"日本", "日本"}, truncated: "2"},
+		{input: []string{"\U0001F600\U0001F600"}, expected: []string{"\U0001F600"}, truncated: "1"},
+	}
+	sanitizer := NewAnnotationValueLengthSanitizer(6, zap.NewNop())
+	for _, test := range tests {
+		span := &zc.Span{}
+		for _, value := range test.input {
+			span.Annotations = append(span.Annotations, &zc.Annotation{Value: value})
+		}
+		span, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, annotationValues(span), "%q", test.input)
+		if test.truncated == "" {
+			assert.Empty(t, span.BinaryAnnotations)
+		} else {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(truncatedAnnotationValueTag, test.truncated)}, span.BinaryAnnotations)
+		}
+	}
+}
+
+func TestAnnotationValueLengthSanitizerCoreAnnotations(t *testing.T) {
+	span := &zc.Span{Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV}}}
+	span, err := NewAnnotationValueLengthSanitizer(1, zap.NewNop()).Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, []string{zc.SERVER_RECV}, annotationValues(span))
+}
+
+func TestAnnotationValueLengthSanitizerNegative(t *testing.T) {
+	assert.Equal(t, NewNoopSanitizer(), NewAnnotationValueLengthSanitizer(-1, zap.NewNop()))
+}
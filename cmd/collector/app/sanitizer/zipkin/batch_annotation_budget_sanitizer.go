@@ -0,0 +1,122 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const batchAnnotationBudgetExceededTag = "warnBatchAnnotationBudgetExceeded"
+
+// NewBatchAnnotationBudgetSanitizer returns a batch sanitizer that limits the total number of annotations and
+// binary annotations across the spans of a batch to maxTotalAnnotations. Once over budget, annotations are
+// dropped starting with the lowest priority: first timed annotations, then binary annotations, each from the
+// last span of the batch and the last position in the span backwards. Core annotations and sanitizer tags are
+// never dropped. The number of annotations dropped from a span is recorded in a
+// 'warnBatchAnnotationBudgetExceeded' tag, which is not counted against the budget.
+func NewBatchAnnotationBudgetSanitizer(maxTotalAnnotations int, logger *zap.Logger) BatchSanitizer {
+	return &batchAnnotationBudgetSanitizer{max: maxTotalAnnotations, logger: logger}
+}
+
+type batchAnnotationBudgetSanitizer struct {
+	max    int
+	logger *zap.Logger
+}
+
+func (s *batchAnnotationBudgetSanitizer) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	total := 0
+	for _, span := range spans {
+		total += len(span.Annotations) + len(span.BinaryAnnotations)
+	}
+	excess := total - s.max
+	if excess <= 0 {
+		return spans
+	}
+	dropped := make([]int, len(spans))
+	for i := len(spans) - 1; i >= 0 && excess > 0; i-- {
+		n := s.dropAnnotations(spans[i], excess)
+		dropped[i] += n
+		excess -= n
+	}
+	for i := len(spans) - 1; i >= 0 && excess > 0; i-- {
+		n := s.dropBinaryAnnotations(spans[i], excess)
+		dropped[i] += n
+		excess -= n
+	}
+	totalDropped := 0
+	for i, span := range spans {
+		if dropped[i] > 0 {
+			appendStringTag(span, batchAnnotationBudgetExceededTag, strconv.Itoa(dropped[i]))
+			totalDropped += dropped[i]
+		}
+	}
+	s.logger.Warn("Batch annotation budget exceeded",
+		zap.Int("annotations", total),
+		zap.Int("dropped", totalDropped))
+	return spans
+}
+
+// dropAnnotations drops up to max non-core annotations from the end of the span and returns how many were dropped.
+func (s *batchAnnotationBudgetSanitizer) dropAnnotations(span *zc.Span, max int) int {
+	drop := make(map[int]struct{})
+	for i := len(span.Annotations) - 1; i >= 0 && len(drop) < max; i-- {
+		if !isCoreAnnotation(span.Annotations[i]) {
+			drop[i] = struct{}{}
+		}
+	}
+	if len(drop) == 0 {
+		return 0
+	}
+	annos := make([]*zc.Annotation, 0, len(span.Annotations)-len(drop))
+	for i, anno := range span.Annotations {
+		if _, ok := drop[i]; !ok {
+			annos = append(annos, anno)
+		}
+	}
+	span.Annotations = annos
+	return len(drop)
+}
+
+// dropBinaryAnnotations drops up to max binary annotations other than sanitizer tags from the end of the span
+// and returns how many were dropped.
+func (s *batchAnnotationBudgetSanitizer) dropBinaryAnnotations(span *zc.Span, max int) int {
+	drop := make(map[int]struct{})
+	for i := len(span.BinaryAnnotations) - 1; i >= 0 && len(drop) < max; i-- {
+		if !isSanitizerTag(span.BinaryAnnotations[i].Key) {
+			drop[i] = struct{}{}
+		}
+	}
+	if len(drop) == 0 {
+		return 0
+	}
+	binAnnos := make([]*zc.BinaryAnnotation, 0, len(span.BinaryAnnotations)-len(drop))
+	for i, binAnno := range span.BinaryAnnotations {
+		if _, ok := drop[i]; !ok {
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	span.BinaryAnnotations = binAnnos
+	return len(drop)
+}
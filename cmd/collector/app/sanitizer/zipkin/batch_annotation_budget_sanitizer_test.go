@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestBatchAnnotationBudgetSanitizer(t *testing.T) {
+	logger, logBuf := testutils.NewLogger()
+	sanitizer := NewBatchAnnotationBudgetSanitizer(6, logger)
+	spans := sanitizer.SanitizeBatch([]*zc.Span{
+		{
+			Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV}, {Value: "log-1"}, {Value: zc.SERVER_SEND}},
+			BinaryAnnotations: []*zc.BinaryAnnotation{
+				stringTag("http.method", "GET"),
+				stringTag("http.url", "/orders"),
+			},
+		},
+		{
+			Annotations: []*zc.Annotation{{Value: zc.CLIENT_SEND}, {Value: "log-2"}},
+			BinaryAnnotations: []*zc.BinaryAnnotation{
+				stringTag("db.type", "sql"),
+				stringTag(negativeDurationTag, "-1"),
+			},
+		},
+	})
+	assert.Equal(t, []string{zc.SERVER_RECV, zc.SERVER_SEND}, annotationValues(spans[0]))
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("http.method", "GET"),
+		stringTag("http.url", "/orders"),
+		stringTag(batchAnnotationBudgetExceededTag, "1"),
+	}, spans[0].BinaryAnnotations)
+	assert.Equal(t, []string{zc.CLIENT_SEND}, annotationValues(spans[1]))
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag(negativeDurationTag, "-1"),
+		stringTag(batchAnnotationBudgetExceededTag, "2"),
+	}, spans[1].BinaryAnnotations)
+	assert.Contains(t, logBuf.String(), "Batch annotation budget exceeded")
+}
+
+func TestBatchAnnotationBudgetSanitizerWithinBudget(t *testing.T) {
+	logger, logBuf := testutils.NewLogger()
+	span := &zc.Span{Annotations: []*zc.Annotation{{Value: "log"}}}
+	spans := NewBatchAnnotationBudgetSanitizer(1, logger).SanitizeBatch([]*zc.Span{span})
+	assert.Equal(t, []string{"log"}, annotationValues(spans[0]))
+	assert.Empty(t, spans[0].BinaryAnnotations)
+	assert.Empty(t, logBuf.String())
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// BatchSanitizer sanitizes a batch of spans as a whole. Unlike Sanitizer it can look across
+// the spans of the batch, and it can drop spans from the batch.
+type BatchSanitizer interface {
+	SanitizeBatch(spans []*zc.Span) []*zc.Span
+}
+
+// filterSpans returns the spans for which keep returns true, preserving their order.
+// The passed slice is not modified.
+func filterSpans(spans []*zc.Span, keep func(span *zc.Span) bool) []*zc.Span {
+	kept := make([]*zc.Span, 0, len(spans))
+	for _, span := range spans {
+		if keep(span) {
+			kept = append(kept, span)
+		}
+	}
+	return kept
+}
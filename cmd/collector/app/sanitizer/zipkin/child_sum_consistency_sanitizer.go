@@ -0,0 +1,113 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const childDurationExceedsParentTag = "warnChildDurationExceedsParent"
+
+// NewChildSumConsistencySanitizer returns a batch sanitizer that checks, for each trace of the batch, that
+// the time covered by the children of a span fits in the span's duration. The coverage is the length of
+// the union of the child intervals, so overlapping children are not counted twice. When the coverage
+// exceeds the parent's duration by more than the tolerance, the excess in microseconds is recorded in
+// a 'warnChildDurationExceedsParent' tag on the parent. Spans without timestamp or duration are ignored.
+func NewChildSumConsistencySanitizer(tolerance time.Duration, logger *zap.Logger) BatchSanitizer {
+	return &childSumConsistencySanitizer{
+		tolerance: int64(tolerance / time.Microsecond),
+		log:       spanLogger{logger},
+	}
+}
+
+type childSumConsistencySanitizer struct {
+	tolerance int64
+	log       spanLogger
+}
+
+type spanRef struct {
+	traceID int64
+	spanID  int64
+}
+
+func (s *childSumConsistencySanitizer) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	children := make(map[spanRef][]interval)
+	for _, span := range spans {
+		if span.ParentID == nil || span.Timestamp == nil || span.Duration == nil {
+			continue
+		}
+		parent := spanRef{traceID: span.TraceID, spanID: *span.ParentID}
+		children[parent] = append(children[parent], interval{start: *span.Timestamp, end: *span.Timestamp + *span.Duration})
+	}
+	for _, span := range spans {
+		intervals, ok := children[spanRef{traceID: span.TraceID, spanID: span.ID}]
+		if !ok || span.Duration == nil {
+			continue
+		}
+		excess := intervalUnionLength(intervals) - *span.Duration
+		if excess <= s.tolerance {
+			continue
+		}
+		s.log.ForSpan(span).Debug("Children cover more than the span duration", zap.Int64("excess", excess))
+		appendStringTag(span, childDurationExceedsParentTag, strconv.FormatInt(excess, 10))
+	}
+	return spans
+}
+
+type interval struct {
+	start, end int64
+}
+
+type byStart []interval
+
+func (s byStart) Len() int           { return len(s) }
+func (s byStart) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byStart) Less(i, j int) bool { return s[i].start < s[j].start }
+
+// intervalUnionLength returns the total length covered by the intervals. The slice is sorted in place.
+func intervalUnionLength(intervals []interval) int64 {
+	sort.Sort(byStart(intervals))
+	total := int64(0)
+	var current *interval
+	for i := range intervals {
+		next := intervals[i]
+		if current != nil && next.start <= current.end {
+			if next.end > current.end {
+				current.end = next.end
+			}
+			continue
+		}
+		if current != nil {
+			total += current.end - current.start
+		}
+		current = &next
+	}
+	if current != nil {
+		total += current.end - current.start
+	}
+	return total
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func timedSpan(id int64, parentID *int64, timestamp, duration int64) *zc.Span {
+	return &zc.Span{TraceID: 1, ID: id, ParentID: parentID, Timestamp: &timestamp, Duration: &duration}
+}
+
+func TestChildSumConsistencySanitizer(t *testing.T) {
+	root, parent := int64(1), int64(2)
+	otherTrace := timedSpan(2, nil, 0, 1)
+	otherTrace.TraceID = 2
+	spans := []*zc.Span{
+		timedSpan(1, nil, 0, 100),
+		timedSpan(2, &root, 10, 50),
+		timedSpan(3, &root, 40, 50),
+		timedSpan(4, &parent, 10, 40),
+		timedSpan(5, &parent, 30, 40),
+		otherTrace,
+	}
+	sanitizer := NewChildSumConsistencySanitizer(5*time.Microsecond, zap.NewNop())
+	spans = sanitizer.SanitizeBatch(spans)
+	assert.Len(t, spans, 6)
+	assert.Empty(t, spans[0].BinaryAnnotations)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(childDurationExceedsParentTag, "10")}, spans[1].BinaryAnnotations)
+	assert.Empty(t, spans[5].BinaryAnnotations)
+}
+
+func TestIntervalUnionLength(t *testing.T) {
+	assert.Equal(t, int64(0), intervalUnionLength(nil))
+	assert.Equal(t, int64(90), intervalUnionLength([]interval{{40, 90}, {10, 60}, {0, 20}}))
+	assert.Equal(t, int64(20), intervalUnionLength([]interval{{50, 60}, {0, 10}}))
+	assert.Equal(t, int64(30), intervalUnionLength([]interval{{0, 30}, {5, 10}}))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"context"
+	"sync"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// endpointCopies maps the endpoints of a span to their copies, so that annotations sharing an endpoint
+// share its copy as well. They are pooled since they are only needed while a span is being copied.
+var endpointCopies = sync.Pool{
+	New: func() interface{} {
+		return make(map[*zc.Endpoint]*zc.Endpoint)
+	},
+}
+
+// NewCopyOnWriteSanitizer returns a sanitizer that passes a deep copy of the span to the inner sanitizer,
+// so that the span it is given is never modified, e.g. when it is still referenced elsewhere. The copy
+// includes the annotations, binary annotations, their values and their endpoints.
+func NewCopyOnWriteSanitizer(inner Sanitizer) Sanitizer {
+	return &copyOnWriteSanitizer{inner: inner}
+}
+
+type copyOnWriteSanitizer struct {
+	inner Sanitizer
+}
+
+func (s *copyOnWriteSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return s.inner.Sanitize(copySpan(span))
+}
+
+// SanitizeCtx passes the context on to the inner sanitizer if it implements ContextSanitizer.
+func (s *copyOnWriteSanitizer) SanitizeCtx(ctx context.Context, span *zc.Span) (*zc.Span, error) {
+	return sanitizeCtx(ctx, s.inner, copySpan(span))
+}
+
+// copySpan returns a deep copy of the span. The annotations, binary annotations and values of the copy
+// are each allocated in a single block.
+func copySpan(span *zc.Span) *zc.Span {
+	endpoints := endpointCopies.Get().(map[*zc.Endpoint]*zc.Endpoint)
+	defer func() {
+		for k := range endpoints {
+			delete(endpoints, k)
+		}
+		endpointCopies.Put(endpoints)
+	}()
+	copyEndpoint := func(endpoint *zc.Endpoint) *zc.Endpoint {
+		if endpoint == nil {
+			return nil
+		}
+		c, ok := endpoints[endpoint]
+		if !ok {
+			c = &zc.Endpoint{}
+			*c = *endpoint
+			endpoints[endpoint] = c
+		}
+		return c
+	}
+
+	c := &zc.Span{}
+	*c = *span
+	c.ParentID = copyInt64(span.ParentID)
+	c.Timestamp = copyInt64(span.Timestamp)
+	c.Duration = copyInt64(span.Duration)
+	if span.Annotations != nil {
+		annos := make([]zc.Annotation, len(span.Annotations))
+		c.Annotations = make([]*zc.Annotation, len(span.Annotations))
+		for i, anno := range span.Annotations {
+			annos[i] = *anno
+			annos[i].Host = copyEndpoint(anno.Host)
+			c.Annotations[i] = &annos[i]
+		}
+	}
+	if span.BinaryAnnotations != nil {
+		size := 0
+		for _, binAnno := range span.BinaryAnnotations {
+			size += len(binAnno.Value)
+		}
+		values := make([]byte, 0, size)
+		binAnnos := make([]zc.BinaryAnnotation, len(span.BinaryAnnotations))
+		c.BinaryAnnotations = make([]*zc.BinaryAnnotation, len(span.BinaryAnnotations))
+		for i, binAnno := range span.BinaryAnnotations {
+			binAnnos[i] = *binAnno
+			binAnnos[i].Host = copyEndpoint(binAnno.Host)
+			if binAnno.Value != nil {
+				start := len(values)
+				values = append(values, binAnno.Value...)
+				// the capacity is limited so that appending to a value cannot overwrite the next one
+				binAnnos[i].Value = values[start:len(values):len(values)]
+			}
+			c.BinaryAnnotations[i] = &binAnnos[i]
+		}
+	}
+	return c
+}
+
+func copyInt64(value *int64) *int64 {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	return &c
+}
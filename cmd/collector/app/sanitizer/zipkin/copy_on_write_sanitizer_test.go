@@ -0,0 +1,101 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func cowSpan(n int) *zc.Span {
+	host := &zc.Endpoint{ServiceName: "frontend", Ipv4: 1, Port: 80}
+	parentID, timestamp, duration := int64(0), int64(100), int64(-1)
+	span := &zc.Span{TraceID: 1, ID: 2, ParentID: &parentID, Timestamp: &timestamp, Duration: &duration}
+	for i := 0; i < n; i++ {
+		span.Annotations = append(span.Annotations, &zc.Annotation{Timestamp: timestamp + int64(i), Value: "event", Host: host})
+		binAnno := stringTag("key-"+strconv.Itoa(i), "value")
+		binAnno.Host = host
+		span.BinaryAnnotations = append(span.BinaryAnnotations, binAnno)
+	}
+	span.BinaryAnnotations = append(span.BinaryAnnotations, stringTag("error", "true"))
+	return span
+}
+
+// mutatingSanitizer modifies every part of the span in place
+type mutatingSanitizer struct{}
+
+func (mutatingSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	*span.ParentID, *span.Timestamp, *span.Duration = 7, 7, 7
+	span.Annotations[0].Value = "changed"
+	span.Annotations[0].Host.ServiceName = "changed"
+	span.BinaryAnnotations[0].Value[0] = 'V'
+	span.BinaryAnnotations[0].Value = append(span.BinaryAnnotations[0].Value, '!')
+	span.BinaryAnnotations[1].Host.Port = 7
+	span.Annotations = span.Annotations[:1]
+	return span, nil
+}
+
+func TestCopyOnWriteSanitizer(t *testing.T) {
+	span := cowSpan(3)
+	sanitizer := NewCopyOnWriteSanitizer(mutatingSanitizer{})
+	actual, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, cowSpan(3), span)
+
+	assert.Equal(t, int64(7), *actual.Duration)
+	assert.Len(t, actual.Annotations, 1)
+	assert.Equal(t, "changed", actual.Annotations[0].Host.ServiceName)
+	assert.Equal(t, "Value!", string(actual.BinaryAnnotations[0].Value))
+	assert.Equal(t, "value", string(actual.BinaryAnnotations[1].Value))
+	assert.True(t, actual.BinaryAnnotations[0].Host == actual.Annotations[0].Host)
+}
+
+func TestCopyOnWriteSanitizerNilFields(t *testing.T) {
+	span := &zc.Span{Name: "name"}
+	actual, err := NewCopyOnWriteSanitizer(NewNoopSanitizer()).Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, span, actual)
+	assert.False(t, span == actual)
+}
+
+// BenchmarkInPlaceSanitizer     	12544803	        97.78 ns/op	       0 B/op	       0 allocs/op
+func BenchmarkInPlaceSanitizer(b *testing.B) {
+	benchmarkSanitizer(b, NewChainedSanitizer(NewSpanDurationSanitizer(zap.NewNop()), NewErrorTagSanitizer()))
+}
+
+// BenchmarkCopyOnWriteSanitizer 	  498012	      2489 ns/op	    3112 B/op	      18 allocs/op
+func BenchmarkCopyOnWriteSanitizer(b *testing.B) {
+	benchmarkSanitizer(b, NewCopyOnWriteSanitizer(NewChainedSanitizer(NewSpanDurationSanitizer(zap.NewNop()), NewErrorTagSanitizer())))
+}
+
+func benchmarkSanitizer(b *testing.B, sanitizer Sanitizer) {
+	span := cowSpan(20)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sanitizer.Sanitize(span)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewCoreAnnotationDedupSanitizer returns a sanitizer that removes repeated cs, cr, sr and ss annotations,
+// e.g. from client retries, keeping the first occurrence of each value for each endpoint. Endpoints are
+// compared by value. Other annotations are left untouched.
+func NewCoreAnnotationDedupSanitizer(logger *zap.Logger) Sanitizer {
+	return &coreAnnotationDedupSanitizer{log: spanLogger{logger}}
+}
+
+type coreAnnotationDedupSanitizer struct {
+	log spanLogger
+}
+
+type coreAnnotationKey struct {
+	value   string
+	host    zc.Endpoint
+	hasHost bool
+}
+
+func (s *coreAnnotationDedupSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	seen := make(map[coreAnnotationKey]struct{})
+	annos := span.Annotations[:0]
+	for _, anno := range span.Annotations {
+		if isCoreAnnotation(anno) {
+			key := coreAnnotationKey{value: anno.Value}
+			if anno.Host != nil {
+				key.host, key.hasHost = *anno.Host, true
+			}
+			if _, ok := seen[key]; ok {
+				s.log.ForSpan(span).Debug("Removed duplicate core annotation",
+					zap.String("value", anno.Value), zap.Int64("timestamp", anno.Timestamp))
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		annos = append(annos, anno)
+	}
+	span.Annotations = annos
+	return span, nil
+}
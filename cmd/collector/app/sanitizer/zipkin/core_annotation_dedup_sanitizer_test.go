@@ -0,0 +1,63 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestCoreAnnotationDedupSanitizer(t *testing.T) {
+	client := &zc.Endpoint{ServiceName: "frontend", Ipv4: 1, Port: 80}
+	server := &zc.Endpoint{ServiceName: "backend", Ipv4: 2, Port: 80}
+	annos := []*zc.Annotation{
+		{Timestamp: 100, Value: zc.CLIENT_SEND, Host: client},
+		{Timestamp: 100, Value: zc.CLIENT_SEND, Host: &zc.Endpoint{ServiceName: "frontend", Ipv4: 1, Port: 80}},
+		{Timestamp: 110, Value: zc.SERVER_RECV, Host: server},
+		{Timestamp: 110, Value: zc.SERVER_RECV, Host: client},
+		{Timestamp: 120, Value: "retry", Host: client},
+		{Timestamp: 120, Value: "retry", Host: client},
+		{Timestamp: 130, Value: zc.CLIENT_RECV},
+		{Timestamp: 140, Value: zc.CLIENT_RECV},
+		{Timestamp: 150, Value: zc.CLIENT_RECV, Host: client},
+	}
+	logger, log := testutils.NewLogger()
+	span, err := NewCoreAnnotationDedupSanitizer(logger).Sanitize(&zc.Span{Annotations: annos})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.Annotation{
+		{Timestamp: 100, Value: zc.CLIENT_SEND, Host: client},
+		{Timestamp: 110, Value: zc.SERVER_RECV, Host: server},
+		{Timestamp: 110, Value: zc.SERVER_RECV, Host: client},
+		{Timestamp: 120, Value: "retry", Host: client},
+		{Timestamp: 120, Value: "retry", Host: client},
+		{Timestamp: 130, Value: zc.CLIENT_RECV},
+		{Timestamp: 150, Value: zc.CLIENT_RECV, Host: client},
+	}, span.Annotations)
+	if assert.Len(t, log.Lines(), 2) {
+		assert.Contains(t, log.Lines()[0], `"value":"cs","timestamp":100`)
+		assert.Contains(t, log.Lines()[1], `"value":"cr","timestamp":140`)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewDedupBinaryAnnotationSanitizer returns a sanitizer that drops exact duplicate binary annotations,
+// i.e. with the same key, value, type and host endpoint, keeping the first occurrence in place.
+// Annotations with the same key but a different value or host are kept.
+func NewDedupBinaryAnnotationSanitizer() Sanitizer {
+	return &dedupBinaryAnnotationSanitizer{}
+}
+
+type dedupBinaryAnnotationSanitizer struct {
+}
+
+type binaryAnnotationIdentity struct {
+	key            string
+	value          string
+	annotationType zc.AnnotationType
+	hasHost        bool
+	host           zc.Endpoint
+}
+
+func (s *dedupBinaryAnnotationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if len(span.BinaryAnnotations) < 2 {
+		return span, nil
+	}
+	seen := make(map[binaryAnnotationIdentity]struct{}, len(span.BinaryAnnotations))
+	binAnnos := span.BinaryAnnotations[:0]
+	for _, binAnno := range span.BinaryAnnotations {
+		identity := binaryAnnotationIdentity{
+			key:            binAnno.Key,
+			value:          string(binAnno.Value),
+			annotationType: binAnno.AnnotationType,
+		}
+		if binAnno.Host != nil {
+			identity.hasHost = true
+			identity.host = *binAnno.Host
+		}
+		if _, ok := seen[identity]; ok {
+			continue
+		}
+		seen[identity] = struct{}{}
+		binAnnos = append(binAnnos, binAnno)
+	}
+	span.BinaryAnnotations = binAnnos
+	return span, nil
+}
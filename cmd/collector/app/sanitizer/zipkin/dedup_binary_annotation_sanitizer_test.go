@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestDedupBinaryAnnotationSanitizer(t *testing.T) {
+	withHost := func(binAnno *zc.BinaryAnnotation, service string) *zc.BinaryAnnotation {
+		binAnno.Host = &zc.Endpoint{ServiceName: service}
+		return binAnno
+	}
+	span, err := NewDedupBinaryAnnotationSanitizer().Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("component", "http"),
+			withHost(stringTag("peer.service", "db"), "frontend"),
+			stringTag("component", "http"),
+			stringTag("component", "grpc"),
+			withHost(stringTag("peer.service", "db"), "backend"),
+			withHost(stringTag("peer.service", "db"), "frontend"),
+			{Key: "component", Value: []byte("http"), AnnotationType: zc.AnnotationType_BYTES},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("component", "http"),
+		withHost(stringTag("peer.service", "db"), "frontend"),
+		stringTag("component", "grpc"),
+		withHost(stringTag("peer.service", "db"), "backend"),
+		{Key: "component", Value: []byte("http"), AnnotationType: zc.AnnotationType_BYTES},
+	}, span.BinaryAnnotations)
+}
+
+// BenchmarkDedupBinaryAnnotationSanitizer 	  181238	      6838 ns/op	    5682 B/op	      53 allocs/op
+func BenchmarkDedupBinaryAnnotationSanitizer(b *testing.B) {
+	host := &zc.Endpoint{ServiceName: "frontend", Ipv4: 1, Port: 80}
+	binAnnos := make([]*zc.BinaryAnnotation, 50)
+	for i := range binAnnos {
+		binAnnos[i] = stringTag("key-"+strconv.Itoa(i%40), "value")
+		binAnnos[i].Host = host
+	}
+	sanitizer := NewDedupBinaryAnnotationSanitizer()
+	span := &zc.Span{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		span.BinaryAnnotations = append(span.BinaryAnnotations[:0], binAnnos...)
+		sanitizer.Sanitize(span)
+	}
+}
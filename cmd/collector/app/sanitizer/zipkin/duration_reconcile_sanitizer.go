@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const durationAnnotationDisagreementTag = "warnDurationAnnotationDisagreement"
+
+// NewDurationReconcileSanitizer returns a sanitizer that compares the end of the span computed from its
+// timestamp and duration with the last 'cr' or 'ss' annotation. When they differ by more than the tolerance,
+// the difference in microseconds is recorded in a 'warnDurationAnnotationDisagreement' tag, and if repair
+// is set, the duration is changed to end at the annotation.
+func NewDurationReconcileSanitizer(tolerance time.Duration, logger *zap.Logger, repair bool) Sanitizer {
+	return &durationReconcileSanitizer{
+		tolerance: int64(tolerance / time.Microsecond),
+		log:       spanLogger{logger},
+		repair:    repair,
+	}
+}
+
+type durationReconcileSanitizer struct {
+	tolerance int64
+	log       spanLogger
+	repair    bool
+}
+
+func (s *durationReconcileSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Timestamp == nil || span.Duration == nil {
+		return span, nil
+	}
+	var end *zc.Annotation
+	for _, anno := range span.Annotations {
+		if (anno.Value == zc.CLIENT_RECV || anno.Value == zc.SERVER_SEND) && (end == nil || anno.Timestamp > end.Timestamp) {
+			end = anno
+		}
+	}
+	if end == nil {
+		return span, nil
+	}
+	delta := *span.Timestamp + *span.Duration - end.Timestamp
+	if delta <= s.tolerance && -delta <= s.tolerance {
+		return span, nil
+	}
+	s.log.ForSpan(span).Debug("Duration disagrees with annotations", zap.Int64("delta", delta))
+	appendStringTag(span, durationAnnotationDisagreementTag, strconv.FormatInt(delta, 10))
+	if s.repair && end.Timestamp >= *span.Timestamp {
+		duration := end.Timestamp - *span.Timestamp
+		span.Duration = &duration
+	}
+	return span, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func reconcileTestSpan(duration int64) *zc.Span {
+	timestamp := int64(1000)
+	return &zc.Span{
+		Timestamp: &timestamp,
+		Duration:  &duration,
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Timestamp: 1000},
+			{Value: zc.CLIENT_RECV, Timestamp: 1500},
+		},
+	}
+}
+
+func TestDurationReconcileSanitizer(t *testing.T) {
+	tests := []struct {
+		duration int64
+		repair   bool
+		expected int64
+		tag      string
+	}{
+		{duration: 500, expected: 500},
+		{duration: 510, expected: 510},
+		{duration: 490, expected: 490},
+		{duration: 800, expected: 800, tag: "300"},
+		{duration: 800, repair: true, expected: 500, tag: "300"},
+		{duration: 100, repair: true, expected: 500, tag: "-400"},
+	}
+	for _, test := range tests {
+		sanitizer := NewDurationReconcileSanitizer(10*time.Microsecond, zap.NewNop(), test.repair)
+		span, err := sanitizer.Sanitize(reconcileTestSpan(test.duration))
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, *span.Duration)
+		if test.tag == "" {
+			assert.Empty(t, span.BinaryAnnotations)
+		} else {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(durationAnnotationDisagreementTag, test.tag)}, span.BinaryAnnotations)
+		}
+	}
+}
+
+func TestDurationReconcileSanitizerNoEndAnnotation(t *testing.T) {
+	sanitizer := NewDurationReconcileSanitizer(0, zap.NewNop(), true)
+	span := reconcileTestSpan(800)
+	span.Annotations = span.Annotations[:1]
+	span, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, int64(800), *span.Duration)
+	assert.Empty(t, span.BinaryAnnotations)
+}
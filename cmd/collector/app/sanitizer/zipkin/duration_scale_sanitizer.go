@@ -0,0 +1,63 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"math"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const rescaledDurationTag = "rescaledDuration"
+
+// NewDurationScaleSanitizer returns a sanitizer that multiplies the duration of spans by factor, e.g. 1000 for
+// client libraries that report milliseconds instead of microseconds. Since this is a destructive heuristic,
+// it only applies to the spans of the services for which matches returns true, and the sanitizer does nothing
+// if matches is nil. The original duration is recorded in a 'rescaledDuration' tag.
+func NewDurationScaleSanitizer(logger *zap.Logger, factor int64, matches func(service string) bool) Sanitizer {
+	if matches == nil || factor <= 1 {
+		return NewNoopSanitizer()
+	}
+	return &durationScaleSanitizer{log: spanLogger{logger}, factor: factor, matches: matches}
+}
+
+type durationScaleSanitizer struct {
+	log     spanLogger
+	factor  int64
+	matches func(service string) bool
+}
+
+func (s *durationScaleSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Duration == nil || *span.Duration <= 0 || !s.matches(findServiceName(span)) {
+		return span, nil
+	}
+	if *span.Duration > math.MaxInt64/s.factor {
+		s.log.ForSpan(span).Debug("Duration too long to rescale", zap.Int64("duration", *span.Duration))
+		return span, nil
+	}
+	appendStringTag(span, rescaledDurationTag, strconv.FormatInt(*span.Duration, 10))
+	duration := *span.Duration * s.factor
+	span.Duration = &duration
+	return span, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestDurationScaleSanitizer(t *testing.T) {
+	legacy := func(service string) bool { return service == "legacy" }
+	tests := []struct {
+		service  string
+		duration int64
+		expected int64
+		tagged   bool
+	}{
+		{"legacy", 5, 5000, true},
+		{"modern", 5, 5, false},
+		{"", 5, 5, false},
+		{"legacy", 0, 0, false},
+		{"legacy", math.MaxInt64 / 100, math.MaxInt64 / 100, false},
+	}
+	sanitizer := NewDurationScaleSanitizer(zap.NewNop(), 1000, legacy)
+	for _, test := range tests {
+		duration := test.duration
+		span := &zc.Span{
+			Duration:    &duration,
+			Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV, Host: &zc.Endpoint{ServiceName: test.service}}},
+		}
+		span, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, *span.Duration, test.service)
+		assert.Equal(t, test.duration, duration, "the original duration must not be modified")
+		if test.tagged {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(rescaledDurationTag, "5")}, span.BinaryAnnotations)
+		} else {
+			assert.Empty(t, span.BinaryAnnotations, test.service)
+		}
+	}
+}
+
+func TestDurationScaleSanitizerDisabled(t *testing.T) {
+	assert.Equal(t, NewNoopSanitizer(), NewDurationScaleSanitizer(zap.NewNop(), 1000, nil))
+	assert.Equal(t, NewNoopSanitizer(), NewDurationScaleSanitizer(zap.NewNop(), 1, func(string) bool { return true }))
+}
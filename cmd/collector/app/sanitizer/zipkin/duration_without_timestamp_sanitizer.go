@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const durationNoTimestampTag = "warnDurationNoTimestamp"
+
+// NewDurationWithoutTimestampSanitizer returns a sanitizer that deals with spans that have a duration
+// but no timestamp. The timestamp is set to the earliest annotation timestamp, or, if the span has no
+// annotation with a timestamp, the duration is recorded in a 'warnDurationNoTimestamp' tag.
+func NewDurationWithoutTimestampSanitizer() Sanitizer {
+	return &durationWithoutTimestampSanitizer{}
+}
+
+type durationWithoutTimestampSanitizer struct {
+}
+
+func (s *durationWithoutTimestampSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Duration == nil || (span.Timestamp != nil && *span.Timestamp != 0) {
+		return span, nil
+	}
+	earliest := int64(0)
+	for _, anno := range span.Annotations {
+		if anno.Timestamp != 0 && (earliest == 0 || anno.Timestamp < earliest) {
+			earliest = anno.Timestamp
+		}
+	}
+	if earliest == 0 {
+		appendStringTag(span, durationNoTimestampTag, strconv.FormatInt(*span.Duration, 10))
+		return span, nil
+	}
+	span.Timestamp = &earliest
+	return span, nil
+}
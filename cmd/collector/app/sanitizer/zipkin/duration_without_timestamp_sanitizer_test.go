@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestDurationWithoutTimestampSanitizer(t *testing.T) {
+	zero := int64(0)
+	timestamp := int64(100)
+	duration := int64(50)
+	tests := []struct {
+		timestamp   *int64
+		duration    *int64
+		annotations []int64
+		expected    int64
+		tagged      bool
+	}{
+		{duration: &duration, annotations: []int64{300, 200, 0}, expected: 200},
+		{timestamp: &zero, duration: &duration, annotations: []int64{300}, expected: 300},
+		{duration: &duration, annotations: []int64{0}, tagged: true},
+		{duration: &duration, tagged: true},
+		{timestamp: &timestamp, duration: &duration, annotations: []int64{50}, expected: 100},
+		{annotations: []int64{300}},
+	}
+	sanitizer := NewDurationWithoutTimestampSanitizer()
+	for _, test := range tests {
+		span := &zc.Span{Timestamp: test.timestamp, Duration: test.duration}
+		for _, ts := range test.annotations {
+			span.Annotations = append(span.Annotations, &zc.Annotation{Timestamp: ts})
+		}
+		span, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		if test.tagged {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(durationNoTimestampTag, "50")}, span.BinaryAnnotations)
+		} else {
+			assert.Empty(t, span.BinaryAnnotations)
+			if test.expected != 0 {
+				assert.Equal(t, test.expected, *span.Timestamp)
+			} else {
+				assert.Nil(t, span.Timestamp)
+			}
+		}
+	}
+}
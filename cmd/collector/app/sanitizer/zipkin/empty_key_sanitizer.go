@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewEmptyKeySanitizer returns a sanitizer that removes binary annotations with an empty or whitespace-only
+// key, which cannot be indexed. The other binary annotations are kept in order. The number of removed
+// binary annotations is logged.
+func NewEmptyKeySanitizer(logger *zap.Logger) Sanitizer {
+	return &emptyKeySanitizer{log: spanLogger{logger}}
+}
+
+type emptyKeySanitizer struct {
+	log spanLogger
+}
+
+func (s *emptyKeySanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	binAnnos := span.BinaryAnnotations[:0]
+	for _, binAnno := range span.BinaryAnnotations {
+		if strings.TrimSpace(binAnno.Key) != "" {
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	if dropped := len(span.BinaryAnnotations) - len(binAnnos); dropped > 0 {
+		s.log.ForSpan(span).Debug("Dropped tags with empty keys", zap.Int("dropped", dropped))
+	}
+	span.BinaryAnnotations = binAnnos
+	return span, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	incompleteEndpointTag = "warnIncompleteEndpoint"
+	// minEndpointCompleteness is the score of an endpoint with a service name or an address
+	minEndpointCompleteness = 2
+)
+
+// NewEndpointCompletenessSanitizer returns a sanitizer that scores the completeness of the endpoints
+// referenced by a span with endpointCompleteness, and tags spans with endpoints scoring below
+// minEndpointCompleteness, i.e. with neither a service name nor an address, with 'warnIncompleteEndpoint',
+// recording the number of such endpoints. The span is not otherwise modified.
+func NewEndpointCompletenessSanitizer(logger *zap.Logger) Sanitizer {
+	return &endpointCompletenessSanitizer{log: spanLogger{logger}}
+}
+
+type endpointCompletenessSanitizer struct {
+	log spanLogger
+}
+
+func (s *endpointCompletenessSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	incomplete := 0
+	for _, anno := range span.Annotations {
+		if anno.Host != nil && endpointCompleteness(anno.Host) < minEndpointCompleteness {
+			incomplete++
+		}
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Host != nil && endpointCompleteness(binAnno.Host) < minEndpointCompleteness {
+			incomplete++
+		}
+	}
+	if incomplete == 0 {
+		return span, nil
+	}
+	s.log.ForSpan(span).Debug("Incomplete endpoints", zap.Int("count", incomplete))
+	appendStringTag(span, incompleteEndpointTag, strconv.Itoa(incomplete))
+	return span, nil
+}
+
+// endpointCompleteness scores an endpoint from 0 to 5: 2 points for each of the service name and the IPv4
+// address, which identify the endpoint, and 1 point for the port. A nil endpoint scores 0.
+func endpointCompleteness(endpoint *zc.Endpoint) int {
+	if endpoint == nil {
+		return 0
+	}
+	score := 0
+	if endpoint.ServiceName != "" {
+		score += 2
+	}
+	if endpoint.Ipv4 != 0 {
+		score += 2
+	}
+	if endpoint.Port != 0 {
+		score++
+	}
+	return score
+}
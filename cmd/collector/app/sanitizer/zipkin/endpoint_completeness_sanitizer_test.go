@@ -0,0 +1,67 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestEndpointCompletenessSanitizer(t *testing.T) {
+	complete := &zc.Endpoint{ServiceName: "app", Ipv4: 0x7f000001, Port: 8080}
+	serviceOnly := &zc.Endpoint{ServiceName: "app"}
+	addressOnly := &zc.Endpoint{Ipv4: 0x7f000001}
+	portOnly := &zc.Endpoint{Port: 8080}
+	empty := &zc.Endpoint{}
+
+	assert.Equal(t, 5, endpointCompleteness(complete))
+	assert.Equal(t, 2, endpointCompleteness(serviceOnly))
+	assert.Equal(t, 2, endpointCompleteness(addressOnly))
+	assert.Equal(t, 1, endpointCompleteness(portOnly))
+	assert.Equal(t, 0, endpointCompleteness(empty))
+	assert.Equal(t, 0, endpointCompleteness(nil))
+
+	sanitizer := NewEndpointCompletenessSanitizer(zap.NewNop())
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV, Host: complete}, {Value: "event"}},
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: zc.CLIENT_ADDR, Host: serviceOnly},
+			{Key: zc.SERVER_ADDR, Host: addressOnly},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 2)
+
+	span, err = sanitizer.Sanitize(&zc.Span{
+		Annotations:       []*zc.Annotation{{Value: zc.SERVER_RECV, Host: empty}},
+		BinaryAnnotations: []*zc.BinaryAnnotation{{Key: zc.SERVER_ADDR, Host: portOnly}},
+	})
+	require.NoError(t, err)
+	if assert.Len(t, span.BinaryAnnotations, 2) {
+		assert.Equal(t, stringTag(incompleteEndpointTag, "2"), span.BinaryAnnotations[1])
+	}
+	assert.Equal(t, &zc.Endpoint{}, span.Annotations[0].Host)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const swappedIPv4Tag = "errSwappedIPv4"
+
+// NewEndpointIPv4Sanitizer returns a sanitizer that byte-swaps the IPv4 address of the endpoints of the
+// annotations and binary annotations for which suspect returns true, for clients that write the address
+// in host rather than network byte order. Since telling them apart is a heuristic, the sanitizer does
+// nothing unless enabled is set. The number of swapped endpoints is recorded in an 'errSwappedIPv4' tag.
+func NewEndpointIPv4Sanitizer(logger *zap.Logger, enabled bool, suspect func(endpoint *zc.Endpoint) bool) Sanitizer {
+	if !enabled {
+		return NewNoopSanitizer()
+	}
+	return &endpointIPv4Sanitizer{log: spanLogger{logger}, suspect: suspect}
+}
+
+type endpointIPv4Sanitizer struct {
+	log     spanLogger
+	suspect func(endpoint *zc.Endpoint) bool
+}
+
+func (s *endpointIPv4Sanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	// endpoints are often shared by annotations, and must only be swapped once
+	checked := make(map[*zc.Endpoint]struct{})
+	swapped := 0
+	check := func(endpoint *zc.Endpoint) {
+		if endpoint == nil {
+			return
+		}
+		if _, ok := checked[endpoint]; ok {
+			return
+		}
+		checked[endpoint] = struct{}{}
+		if endpoint.Ipv4 == 0 || !s.suspect(endpoint) {
+			return
+		}
+		ipv4 := uint32(endpoint.Ipv4)
+		endpoint.Ipv4 = int32(ipv4>>24 | ipv4>>8&0xff00 | ipv4<<8&0xff0000 | ipv4<<24)
+		swapped++
+	}
+	for _, anno := range span.Annotations {
+		check(anno.Host)
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		check(binAnno.Host)
+	}
+	if swapped > 0 {
+		s.log.ForSpan(span).Debug("Swapped endpoint IPv4 byte order", zap.Int("endpoints", swapped))
+		appendStringTag(span, swappedIPv4Tag, strconv.Itoa(swapped))
+	}
+	return span, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	ipv4Good    = int32(0x0A000001)  // 10.0.0.1
+	ipv4Swapped = int32(0x0100000A)  // 10.0.0.1 in host byte order
+	ipv4High    = int32(-0x3F57FF00) // 192.168.1.0
+)
+
+// hostOrderPrivate suspects addresses whose last octet looks like the first octet of a private address
+func hostOrderPrivate(endpoint *zc.Endpoint) bool {
+	return endpoint.Ipv4&0xff == 10
+}
+
+func TestEndpointIPv4Sanitizer(t *testing.T) {
+	shared := &zc.Endpoint{ServiceName: "frontend", Ipv4: ipv4Swapped}
+	good := &zc.Endpoint{ServiceName: "backend", Ipv4: ipv4Good}
+	other := &zc.Endpoint{ServiceName: "db", Ipv4: ipv4Swapped}
+	span := &zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Host: shared},
+			{Value: zc.CLIENT_RECV, Host: shared},
+			{Value: zc.SERVER_RECV, Host: good},
+			{Value: "event"},
+		},
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: zc.SERVER_ADDR, Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL, Host: other},
+			{Key: "lc", Value: []byte("x"), AnnotationType: zc.AnnotationType_STRING, Host: shared},
+		},
+	}
+	sanitizer := NewEndpointIPv4Sanitizer(zap.NewNop(), true, hostOrderPrivate)
+	span, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, ipv4Good, shared.Ipv4)
+	assert.Equal(t, ipv4Good, good.Ipv4)
+	assert.Equal(t, ipv4Good, other.Ipv4)
+	assert.Equal(t, stringTag(swappedIPv4Tag, "2"), span.BinaryAnnotations[2])
+}
+
+func TestEndpointIPv4SanitizerSignBit(t *testing.T) {
+	endpoint := &zc.Endpoint{Ipv4: ipv4High}
+	sanitizer := NewEndpointIPv4Sanitizer(zap.NewNop(), true, func(*zc.Endpoint) bool { return true })
+	_, err := sanitizer.Sanitize(&zc.Span{Annotations: []*zc.Annotation{{Host: endpoint}}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0x0001A8C0), endpoint.Ipv4)
+}
+
+func TestEndpointIPv4SanitizerDisabled(t *testing.T) {
+	sanitizer := NewEndpointIPv4Sanitizer(zap.NewNop(), false, hostOrderPrivate)
+	assert.Equal(t, NewNoopSanitizer(), sanitizer)
+}
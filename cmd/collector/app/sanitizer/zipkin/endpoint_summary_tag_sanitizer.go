@@ -0,0 +1,116 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const endpointSummaryKey = "jaeger.endpoint"
+
+// NewEndpointSummaryTagSanitizer returns a sanitizer that summarizes the primary endpoint of a span into a
+// 'jaeger.endpoint' tag of the form 'service@ip:port'. The server endpoint is preferred, i.e. the host of
+// the 'sr' or 'ss' annotations or of the 'sa' address, then the client endpoint, then any other endpoint.
+// Missing parts, e.g. the IP address or the port, are omitted. An existing summary tag is updated in place.
+func NewEndpointSummaryTagSanitizer() Sanitizer {
+	return &endpointSummaryTagSanitizer{}
+}
+
+type endpointSummaryTagSanitizer struct {
+}
+
+func (s *endpointSummaryTagSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	endpoint := primaryEndpoint(span)
+	if endpoint == nil {
+		return span, nil
+	}
+	setStringTag(span, endpointSummaryKey, endpointSummary(endpoint))
+	return span, nil
+}
+
+// primaryEndpoint returns the server endpoint of the span if any, then the client endpoint,
+// then the first endpoint found on the span, excluding the 'ca' address.
+func primaryEndpoint(span *zc.Span) *zc.Endpoint {
+	var server, client, other *zc.Endpoint
+	for _, anno := range span.Annotations {
+		if anno.Host == nil {
+			continue
+		}
+		switch anno.Value {
+		case zc.SERVER_RECV, zc.SERVER_SEND:
+			if server == nil {
+				server = anno.Host
+			}
+		case zc.CLIENT_SEND, zc.CLIENT_RECV:
+			if client == nil {
+				client = anno.Host
+			}
+		default:
+			if other == nil {
+				other = anno.Host
+			}
+		}
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Host == nil {
+			continue
+		}
+		switch binAnno.Key {
+		case zc.SERVER_ADDR:
+			if server == nil {
+				server = binAnno.Host
+			}
+		case zc.CLIENT_ADDR:
+		default:
+			if other == nil {
+				other = binAnno.Host
+			}
+		}
+	}
+	if server != nil {
+		return server
+	}
+	if client != nil {
+		return client
+	}
+	return other
+}
+
+// endpointSummary formats the endpoint as 'service@ip:port', omitting the missing parts.
+func endpointSummary(endpoint *zc.Endpoint) string {
+	summary := endpoint.ServiceName
+	if endpoint.Ipv4 != 0 {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, uint32(endpoint.Ipv4))
+		if summary != "" {
+			summary += "@"
+		}
+		summary += ip.String()
+	}
+	if endpoint.Port != 0 {
+		summary += ":" + strconv.Itoa(int(uint16(endpoint.Port)))
+	}
+	return summary
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestEndpointSummaryTagSanitizer(t *testing.T) {
+	client := &zc.Endpoint{ServiceName: "frontend", Ipv4: 0x0A000001, Port: 8080}
+	server := &zc.Endpoint{ServiceName: "backend", Ipv4: -1062731519, Port: -1}
+	sanitizer := NewEndpointSummaryTagSanitizer()
+
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Host: client},
+			{Value: zc.SERVER_RECV, Host: server},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(endpointSummaryKey, "backend@192.168.1.1:65535")}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(endpointSummaryKey, "backend@192.168.1.1:65535")}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{{Value: zc.CLIENT_SEND, Host: &zc.Endpoint{ServiceName: "frontend"}}},
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: zc.CLIENT_ADDR, Host: &zc.Endpoint{ServiceName: "browser"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, stringTag(endpointSummaryKey, "frontend"), span.BinaryAnnotations[1])
+
+	span, err = sanitizer.Sanitize(&zc.Span{})
+	require.NoError(t, err)
+	assert.Empty(t, span.BinaryAnnotations)
+}
+
+func TestEndpointSummary(t *testing.T) {
+	tests := []struct {
+		endpoint *zc.Endpoint
+		expected string
+	}{
+		{endpoint: &zc.Endpoint{ServiceName: "backend", Ipv4: 0x01020304, Port: 80}, expected: "backend@1.2.3.4:80"},
+		{endpoint: &zc.Endpoint{Ipv4: 0x01020304, Port: 80}, expected: "1.2.3.4:80"},
+		{endpoint: &zc.Endpoint{Ipv4: 0x01020304}, expected: "1.2.3.4"},
+		{endpoint: &zc.Endpoint{ServiceName: "backend"}, expected: "backend"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, endpointSummary(test.endpoint))
+	}
+}
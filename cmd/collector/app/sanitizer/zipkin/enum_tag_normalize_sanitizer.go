@@ -0,0 +1,88 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const normalizedEnumTag = "warnNormalizedEnumTag"
+
+// enumValuePattern matches enum-like values, i.e. single identifiers such as 'SUCCESS' or 'not_found'.
+var enumValuePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_\-]*$`)
+
+// Casing is the letter case enum-like tag values are normalized to.
+type Casing int
+
+const (
+	// LowerCase normalizes values to lower case, e.g. 'success'.
+	LowerCase Casing = iota
+	// UpperCase normalizes values to upper case, e.g. 'SUCCESS'.
+	UpperCase
+)
+
+// NewEnumTagNormalizeSanitizer returns a sanitizer that normalizes the casing of the values of the STRING tags
+// with the given enum-like keys, e.g. 'status', so that 'SUCCESS' and 'Success' group together. Keys are matched
+// case-insensitively, so 'status' also normalizes 'Status'. Values that are not single identifiers, e.g. numbers
+// or free text, are left alone. The keys of the normalized tags are recorded in a 'warnNormalizedEnumTag' tag.
+func NewEnumTagNormalizeSanitizer(keys []string, casing Casing) Sanitizer {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[strings.ToLower(key)] = struct{}{}
+	}
+	return &enumTagNormalizeSanitizer{keys: keySet, casing: casing}
+}
+
+type enumTagNormalizeSanitizer struct {
+	keys   map[string]struct{}
+	casing Casing
+}
+
+func (s *enumTagNormalizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var normalized []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if _, ok := s.keys[strings.ToLower(binAnno.Key)]; !ok || binAnno.AnnotationType != zc.AnnotationType_STRING {
+			continue
+		}
+		value := string(binAnno.Value)
+		if !enumValuePattern.MatchString(value) {
+			continue
+		}
+		if cased := s.applyCasing(value); cased != value {
+			binAnno.Value = []byte(cased)
+			normalized = append(normalized, binAnno.Key)
+		}
+	}
+	if len(normalized) > 0 {
+		appendStringTag(span, normalizedEnumTag, strings.Join(normalized, ","))
+	}
+	return span, nil
+}
+
+func (s *enumTagNormalizeSanitizer) applyCasing(value string) string {
+	if s.casing == UpperCase {
+		return strings.ToUpper(value)
+	}
+	return strings.ToLower(value)
+}
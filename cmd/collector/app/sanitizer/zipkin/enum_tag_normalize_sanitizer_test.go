@@ -0,0 +1,82 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestEnumTagNormalizeSanitizer(t *testing.T) {
+	tests := []struct {
+		casing   Casing
+		value    string
+		expected string
+	}{
+		{casing: LowerCase, value: "SUCCESS", expected: "success"},
+		{casing: LowerCase, value: "Success", expected: "success"},
+		{casing: LowerCase, value: "success", expected: "success"},
+		{casing: UpperCase, value: "not_Found", expected: "NOT_FOUND"},
+		{casing: UpperCase, value: "404", expected: "404"},
+		{casing: UpperCase, value: "Request timed out", expected: "Request timed out"},
+	}
+	for _, test := range tests {
+		sanitizer := NewEnumTagNormalizeSanitizer([]string{"status"}, test.casing)
+		span, err := sanitizer.Sanitize(&zc.Span{
+			BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("status", test.value), stringTag("Status", test.value)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, string(span.BinaryAnnotations[0].Value))
+		assert.Equal(t, test.expected, string(span.BinaryAnnotations[1].Value))
+		if test.expected != test.value {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(normalizedEnumTag, "status,Status")}, span.BinaryAnnotations[2:])
+		} else {
+			assert.Len(t, span.BinaryAnnotations, 2)
+		}
+
+		span, err = sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, string(span.BinaryAnnotations[0].Value))
+		assert.Equal(t, test.expected, string(span.BinaryAnnotations[1].Value))
+	}
+}
+
+func TestEnumTagNormalizeSanitizerMixedCaseKeys(t *testing.T) {
+	sanitizer := NewEnumTagNormalizeSanitizer([]string{"STATUS"}, UpperCase)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("status", "SUCCESS"),
+			stringTag("Status", "Success"),
+			stringTag("http.status", "ok"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("status", "SUCCESS"),
+		stringTag("Status", "SUCCESS"),
+		stringTag("http.status", "ok"),
+		stringTag(normalizedEnumTag, "Status"),
+	}, span.BinaryAnnotations)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	grpcStatusCodeKey  = "rpc.grpc.status_code"
+	grpcStatusClassKey = "rpc.grpc.status_class"
+	errorKey           = "error"
+)
+
+var (
+	// grpcClientErrorCodes are the gRPC status codes caused by the client: CANCELLED, INVALID_ARGUMENT,
+	// NOT_FOUND, ALREADY_EXISTS, PERMISSION_DENIED, FAILED_PRECONDITION, OUT_OF_RANGE and UNAUTHENTICATED.
+	// All other non-zero codes are considered server errors.
+	grpcClientErrorCodes = map[int64]bool{1: true, 3: true, 5: true, 6: true, 7: true, 9: true, 11: true, 16: true}
+)
+
+// NewGRPCStatusSanitizer returns a sanitizer that interprets the 'rpc.grpc.status_code' tag, or its legacy
+// 'grpc.status_code' key which is renamed to the former. It adds an 'rpc.grpc.status_class' tag with the value
+// 'ok', 'client-error' or 'server-error', and for non-zero codes a BOOL 'error' tag unless the span already has
+// an 'error' tag, whatever the case of its key. Because the added 'error' tag is already BOOL, it can be placed
+// before or after the error tag sanitizer in the chain.
+func NewGRPCStatusSanitizer() Sanitizer {
+	return &grpcStatusSanitizer{}
+}
+
+type grpcStatusSanitizer struct {
+}
+
+func (s *grpcStatusSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	status := findBinaryAnnotation(span, grpcStatusCodeKey)
+	if status == nil {
+		if status = findBinaryAnnotation(span, "grpc.status_code"); status == nil {
+			return span, nil
+		}
+		status.Key = grpcStatusCodeKey
+	}
+	if findBinaryAnnotation(span, grpcStatusClassKey) != nil {
+		return span, nil
+	}
+	var (
+		code int64
+		err  error
+	)
+	if status.AnnotationType == zc.AnnotationType_STRING {
+		code, err = strconv.ParseInt(strings.TrimSpace(string(status.Value)), 10, 64)
+	} else {
+		code, err = decodeInt(status)
+	}
+	if err != nil {
+		return span, nil
+	}
+	switch {
+	case code == 0:
+		appendStringTag(span, grpcStatusClassKey, "ok")
+		return span, nil
+	case grpcClientErrorCodes[code]:
+		appendStringTag(span, grpcStatusClassKey, "client-error")
+	default:
+		appendStringTag(span, grpcStatusClassKey, "server-error")
+	}
+	if !hasErrorTag(span) {
+		span.BinaryAnnotations = append(span.BinaryAnnotations, &zc.BinaryAnnotation{
+			Key:            errorKey,
+			Value:          []byte{1},
+			AnnotationType: zc.AnnotationType_BOOL,
+		})
+	}
+	return span, nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestGRPCStatusSanitizer(t *testing.T) {
+	errorTrue := &zc.BinaryAnnotation{Key: "error", Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL}
+	tests := []struct {
+		tags     []*zc.BinaryAnnotation
+		expected []*zc.BinaryAnnotation
+	}{
+		{
+			tags: []*zc.BinaryAnnotation{stringTag("rpc.grpc.status_code", "0")},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("rpc.grpc.status_code", "0"),
+				stringTag(grpcStatusClassKey, "ok"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{{Key: "grpc.status_code", Value: []byte{0, 0, 0, 5}, AnnotationType: zc.AnnotationType_I32}},
+			expected: []*zc.BinaryAnnotation{
+				{Key: "rpc.grpc.status_code", Value: []byte{0, 0, 0, 5}, AnnotationType: zc.AnnotationType_I32},
+				stringTag(grpcStatusClassKey, "client-error"),
+				errorTrue,
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{stringTag("rpc.grpc.status_code", "14"), stringTag("error", "unavailable")},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("rpc.grpc.status_code", "14"),
+				stringTag("error", "unavailable"),
+				stringTag(grpcStatusClassKey, "server-error"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("rpc.grpc.status_code", "14"),
+				{Key: "Error", Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL},
+			},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("rpc.grpc.status_code", "14"),
+				{Key: "Error", Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL},
+				stringTag(grpcStatusClassKey, "server-error"),
+			},
+		},
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag("rpc.grpc.status_code", "OK")},
+			expected: []*zc.BinaryAnnotation{stringTag("rpc.grpc.status_code", "OK")},
+		},
+	}
+	sanitizer := NewGRPCStatusSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: test.tags})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations)
+	}
+}
+
+func TestGRPCStatusSanitizerWithErrorTagSanitizer(t *testing.T) {
+	sanitizer := NewChainedSanitizer(NewGRPCStatusSanitizer(), NewErrorTagSanitizer())
+	span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("rpc.grpc.status_code", "13")}})
+	require.NoError(t, err)
+	assert.Equal(t, &zc.BinaryAnnotation{Key: "error", Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL}, span.BinaryAnnotations[2])
+	assert.Len(t, span.BinaryAnnotations, 3)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	highEntropyValueTag = "warnHighEntropyValue"
+	rawValueSuffix      = ".raw"
+	hashPrefixLength    = 16
+)
+
+// NewHighEntropyValueSanitizer returns a sanitizer that controls the cardinality of the STRING tags with
+// the given keys. A value whose Shannon entropy, in bits per byte, is above entropyThreshold, e.g. a random
+// ID or a hash, is replaced with a prefix of its SHA-256 hash, and the original value is kept in a
+// '<key>.raw' tag. The keys of the replaced tags are recorded in a 'warnHighEntropyValue' tag.
+func NewHighEntropyValueSanitizer(keys []string, entropyThreshold float64) Sanitizer {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[key] = struct{}{}
+	}
+	return &highEntropyValueSanitizer{keys: keySet, threshold: entropyThreshold}
+}
+
+type highEntropyValueSanitizer struct {
+	keys      map[string]struct{}
+	threshold float64
+}
+
+func (s *highEntropyValueSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var replaced []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if _, ok := s.keys[binAnno.Key]; !ok || binAnno.AnnotationType != zc.AnnotationType_STRING {
+			continue
+		}
+		rawKey := binAnno.Key + rawValueSuffix
+		if findBinaryAnnotation(span, rawKey) != nil || shannonEntropy(binAnno.Value) <= s.threshold {
+			continue
+		}
+		appendStringTag(span, rawKey, string(binAnno.Value))
+		sum := sha256.Sum256(binAnno.Value)
+		binAnno.Value = []byte(hex.EncodeToString(sum[:])[:hashPrefixLength])
+		replaced = append(replaced, binAnno.Key)
+	}
+	if len(replaced) > 0 {
+		appendStringTag(span, highEntropyValueTag, strings.Join(replaced, ","))
+	}
+	return span, nil
+}
+
+// shannonEntropy returns the Shannon entropy of the value in bits per byte.
+func shannonEntropy(value []byte) float64 {
+	if len(value) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, b := range value {
+		counts[b]++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / float64(len(value))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestHighEntropyValueSanitizer(t *testing.T) {
+	sanitizer := NewHighEntropyValueSanitizer([]string{"user.id", "request.id"}, 3.5)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("user.id", "aaaabbbb"),
+			stringTag("request.id", "9f86d081884c7d659a2feaa0c55ad015"),
+			stringTag("other.id", "9f86d081884c7d659a2feaa0c55ad015"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("user.id", "aaaabbbb"),
+		stringTag("request.id", "6721246223140285"),
+		stringTag("other.id", "9f86d081884c7d659a2feaa0c55ad015"),
+		stringTag("request.id.raw", "9f86d081884c7d659a2feaa0c55ad015"),
+		stringTag(highEntropyValueTag, "request.id"),
+	}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 5)
+}
+
+func TestShannonEntropy(t *testing.T) {
+	assert.Equal(t, 0.0, shannonEntropy(nil))
+	assert.Equal(t, 0.0, shannonEntropy([]byte("aaaa")))
+	assert.Equal(t, 1.0, shannonEntropy([]byte("aabb")))
+	assert.Equal(t, 2.0, shannonEntropy([]byte("abcd")))
+}
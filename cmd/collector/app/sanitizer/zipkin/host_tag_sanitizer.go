@@ -0,0 +1,61 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	canonicalizedHostTag = "warnCanonicalizedHostTag"
+	netHostNameKey       = "net.host.name"
+)
+
+// hostTagKeys lists the tags describing the host that served the request, most specific first.
+var hostTagKeys = []string{netHostNameKey, "http.server_name", "http.host"}
+
+// NewHostTagCanonicalizeSanitizer returns a sanitizer that consolidates 'http.host', 'http.server_name'
+// and 'net.host.name' string tags into a single 'net.host.name' tag, keeping the most specific
+// non-empty value and removing the others.
+func NewHostTagCanonicalizeSanitizer() Sanitizer {
+	return &hostTagCanonicalizeSanitizer{}
+}
+
+type hostTagCanonicalizeSanitizer struct {
+}
+
+func (s *hostTagCanonicalizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	merged := mergeStringTags(span, hostTagKeys, func(candidates []*zc.BinaryAnnotation) *zc.BinaryAnnotation {
+		var chosen *zc.BinaryAnnotation
+		for _, candidate := range candidates {
+			if len(candidate.Value) > 0 && (chosen == nil || keyRank(candidate, hostTagKeys) < keyRank(chosen, hostTagKeys)) {
+				chosen = candidate
+			}
+		}
+		return chosen
+	})
+	if merged != nil {
+		appendStringTag(span, canonicalizedHostTag, strings.Join(merged, ","))
+	}
+	return span, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func stringTag(key, value string) *zc.BinaryAnnotation {
+	return &zc.BinaryAnnotation{Key: key, Value: []byte(value), AnnotationType: zc.AnnotationType_STRING}
+}
+
+func TestHostTagCanonicalizeSanitizer(t *testing.T) {
+	tests := []struct {
+		tags     []*zc.BinaryAnnotation
+		expected string
+		keys     string
+	}{
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("http.host", "example.com:8080"),
+				stringTag("component", "http"),
+				stringTag("http.server_name", "example.com"),
+				stringTag("net.host.name", "host-1.example.com"),
+			},
+			expected: "host-1.example.com",
+			keys:     "http.host,http.server_name,net.host.name",
+		},
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("net.host.name", ""),
+				stringTag("http.host", "example.com:8080"),
+				stringTag("component", "http"),
+				stringTag("http.server_name", "example.com"),
+			},
+			expected: "example.com",
+			keys:     "net.host.name,http.host,http.server_name",
+		},
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("component", "http"),
+				stringTag("http.host", "example.com:8080"),
+			},
+			expected: "example.com:8080",
+			keys:     "http.host",
+		},
+	}
+	sanitizer := NewHostTagCanonicalizeSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: test.tags})
+		require.NoError(t, err)
+		if assert.Len(t, span.BinaryAnnotations, 3) {
+			assert.Equal(t, "component", span.BinaryAnnotations[0].Key)
+			assert.Equal(t, netHostNameKey, span.BinaryAnnotations[1].Key)
+			assert.Equal(t, test.expected, string(span.BinaryAnnotations[1].Value))
+			assert.Equal(t, canonicalizedHostTag, span.BinaryAnnotations[2].Key)
+			assert.Equal(t, test.keys, string(span.BinaryAnnotations[2].Value))
+		}
+	}
+}
+
+func TestHostTagCanonicalizeSanitizerNoop(t *testing.T) {
+	sanitizer := NewHostTagCanonicalizeSanitizer()
+	for _, tags := range [][]*zc.BinaryAnnotation{
+		nil,
+		{stringTag("net.host.name", "example.com")},
+		{stringTag("http.host", "")},
+	} {
+		span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: tags})
+		require.NoError(t, err)
+		assert.Equal(t, tags, span.BinaryAnnotations)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"math"
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const httpStatusCodeKey = "http.status_code"
+
+// NewHTTPStatusCodeSanitizer returns a sanitizer that converts a STRING 'http.status_code' tag holding
+// an integer, e.g. "200", into an I32 tag, so that all status codes have the same type. Values that are
+// not integers are left untouched.
+func NewHTTPStatusCodeSanitizer() Sanitizer {
+	return &httpStatusCodeSanitizer{}
+}
+
+type httpStatusCodeSanitizer struct {
+}
+
+func (s *httpStatusCodeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Key != httpStatusCodeKey || binAnno.AnnotationType != zc.AnnotationType_STRING {
+			continue
+		}
+		code, err := strconv.Atoi(string(binAnno.Value))
+		if err != nil || code < math.MinInt32 || code > math.MaxInt32 {
+			continue
+		}
+		binAnno.AnnotationType = zc.AnnotationType_I32
+		binAnno.Value = int32Bytes(int32(code))
+	}
+	return span, nil
+}
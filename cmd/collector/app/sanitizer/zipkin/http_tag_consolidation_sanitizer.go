@@ -0,0 +1,97 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	httpTagsConsolidatedTag = "warnHTTPTagsConsolidated"
+	httpMethodKey           = "http.method"
+	httpURLKey              = "http.url"
+	httpTargetKey           = "http.target"
+	httpPathKey             = "http.path"
+)
+
+// NewHTTPTagConsolidationSanitizer returns a sanitizer that reduces the HTTP tags of a span to the canonical
+// set 'http.method', 'http.url', 'http.target' and 'net.host.name'. An absolute 'http.url' is authoritative:
+// 'http.target' is derived from it, and 'net.host.name' is filled from it when missing, since the served host
+// may legitimately differ from the requested one. Without 'http.url', a 'http.path' becomes 'http.target'.
+// The redundant 'http.path' is removed and 'http.method' is upper-cased. The keys of the changed tags
+// are recorded in a 'warnHTTPTagsConsolidated' tag.
+func NewHTTPTagConsolidationSanitizer() Sanitizer {
+	return &httpTagConsolidationSanitizer{}
+}
+
+type httpTagConsolidationSanitizer struct {
+}
+
+func (s *httpTagConsolidationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var changed []string
+	if method := findBinaryAnnotation(span, httpMethodKey); method != nil && method.AnnotationType == zc.AnnotationType_STRING {
+		if normalized := strings.ToUpper(string(method.Value)); normalized != string(method.Value) {
+			method.Value = []byte(normalized)
+			changed = append(changed, httpMethodKey)
+		}
+	}
+	target, host := s.fromURL(span)
+	if target == "" && findBinaryAnnotation(span, httpTargetKey) == nil {
+		if path := findBinaryAnnotation(span, httpPathKey); path != nil && path.AnnotationType == zc.AnnotationType_STRING {
+			target = string(path.Value)
+		}
+	}
+	if target != "" && setStringTag(span, httpTargetKey, target) {
+		changed = append(changed, httpTargetKey)
+	}
+	if host != "" && findBinaryAnnotation(span, netHostNameKey) == nil {
+		appendStringTag(span, netHostNameKey, host)
+		changed = append(changed, netHostNameKey)
+	}
+	if removeBinaryAnnotations(span, httpPathKey) > 0 {
+		changed = append(changed, httpPathKey)
+	}
+	if len(changed) > 0 {
+		appendStringTag(span, httpTagsConsolidatedTag, strings.Join(changed, ","))
+	}
+	return span, nil
+}
+
+// fromURL returns the request target and the host name of the span's absolute 'http.url', if any.
+func (s *httpTagConsolidationSanitizer) fromURL(span *zc.Span) (target string, host string) {
+	binAnno := findBinaryAnnotation(span, httpURLKey)
+	if binAnno == nil || binAnno.AnnotationType != zc.AnnotationType_STRING {
+		return "", ""
+	}
+	u, err := url.Parse(string(binAnno.Value))
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return "", ""
+	}
+	host = u.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return u.RequestURI(), host
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	canonicalizedHTTPVersionTag = "warnCanonicalizedHTTPVersion"
+	httpFlavorKey               = "http.flavor"
+)
+
+// httpVersionKeys lists the tags describing the HTTP protocol version, canonical first.
+var httpVersionKeys = []string{httpFlavorKey, "http.version", "http.protocol"}
+
+// NewHTTPVersionSanitizer returns a sanitizer that consolidates 'http.flavor', 'http.version' and
+// 'http.protocol' string tags into a single 'http.flavor' tag holding a bare version, e.g. "1.1" or "2".
+// The 'HTTP/' prefix is stripped and a '.0' minor version is dropped from versions 2 and above. The keys of
+// the changed tags are recorded in a 'warnCanonicalizedHTTPVersion' tag.
+func NewHTTPVersionSanitizer() Sanitizer {
+	return &httpVersionSanitizer{}
+}
+
+type httpVersionSanitizer struct {
+}
+
+func (s *httpVersionSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	merged := mergeStringTags(span, httpVersionKeys, func(candidates []*zc.BinaryAnnotation) *zc.BinaryAnnotation {
+		var chosen *zc.BinaryAnnotation
+		for _, candidate := range candidates {
+			if len(candidate.Value) > 0 && (chosen == nil || keyRank(candidate, httpVersionKeys) < keyRank(chosen, httpVersionKeys)) {
+				chosen = candidate
+			}
+		}
+		return chosen
+	})
+	flavor := findBinaryAnnotation(span, httpFlavorKey)
+	if flavor != nil && flavor.AnnotationType == zc.AnnotationType_STRING {
+		if version := canonicalHTTPVersion(string(flavor.Value)); version != string(flavor.Value) {
+			flavor.Value = []byte(version)
+			if merged == nil {
+				merged = []string{httpFlavorKey}
+			}
+		}
+	}
+	if merged != nil {
+		appendStringTag(span, canonicalizedHTTPVersionTag, strings.Join(merged, ","))
+	}
+	return span, nil
+}
+
+// canonicalHTTPVersion turns e.g. "HTTP/1.1" into "1.1" and "HTTP/2.0" into "2".
+func canonicalHTTPVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) >= 5 && strings.EqualFold(version[:5], "HTTP/") {
+		version = version[5:]
+	}
+	if strings.HasSuffix(version, ".0") && version != "1.0" {
+		version = strings.TrimSuffix(version, ".0")
+	}
+	return version
+}
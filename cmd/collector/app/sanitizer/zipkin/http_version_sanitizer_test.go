@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestHTTPVersionSanitizer(t *testing.T) {
+	tests := []struct {
+		tags     []*zc.BinaryAnnotation
+		expected []*zc.BinaryAnnotation
+	}{
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag("http.flavor", "1.1")},
+			expected: []*zc.BinaryAnnotation{stringTag("http.flavor", "1.1")},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{stringTag("http.version", "HTTP/1.1")},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("http.flavor", "1.1"),
+				stringTag(canonicalizedHTTPVersionTag, "http.version"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{stringTag("http.protocol", "HTTP/2.0")},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("http.flavor", "2"),
+				stringTag(canonicalizedHTTPVersionTag, "http.protocol"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{stringTag("http.flavor", "http/1.0")},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("http.flavor", "1.0"),
+				stringTag(canonicalizedHTTPVersionTag, "http.flavor"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("http.protocol", "HTTP/1.1"),
+				stringTag("http.flavor", "2"),
+			},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("http.flavor", "2"),
+				stringTag(canonicalizedHTTPVersionTag, "http.protocol,http.flavor"),
+			},
+		},
+	}
+	sanitizer := NewHTTPVersionSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: test.tags})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations)
+	}
+}
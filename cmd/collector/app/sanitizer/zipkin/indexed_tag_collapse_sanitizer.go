@@ -0,0 +1,102 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const collapsedIndexedTagsTag = "warnCollapsedIndexedTags"
+
+// NewIndexedTagCollapseSanitizer returns a sanitizer that collapses tags with keys '<prefix>.<n>', for
+// the given prefixes, when consecutive indices hold identical values. For example 'arg.0', 'arg.1' and
+// 'arg.2' holding the same value are replaced by a single 'arg.0-2' tag. The number of removed tags is
+// recorded in a 'warnCollapsedIndexedTags' tag.
+func NewIndexedTagCollapseSanitizer(prefixes []string) Sanitizer {
+	return &indexedTagCollapseSanitizer{prefixes: prefixes}
+}
+
+type indexedTagCollapseSanitizer struct {
+	prefixes []string
+}
+
+type indexedTag struct {
+	index   int
+	binAnno *zc.BinaryAnnotation
+}
+
+type byIndex []indexedTag
+
+func (s byIndex) Len() int           { return len(s) }
+func (s byIndex) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byIndex) Less(i, j int) bool { return s[i].index < s[j].index }
+
+func (s *indexedTagCollapseSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	removed := make(map[*zc.BinaryAnnotation]struct{})
+	for _, prefix := range s.prefixes {
+		var tags byIndex
+		for _, binAnno := range span.BinaryAnnotations {
+			if !strings.HasPrefix(binAnno.Key, prefix+".") {
+				continue
+			}
+			index, err := strconv.Atoi(binAnno.Key[len(prefix)+1:])
+			if err != nil || index < 0 {
+				continue
+			}
+			tags = append(tags, indexedTag{index: index, binAnno: binAnno})
+		}
+		sort.Stable(tags)
+		for start := 0; start < len(tags); {
+			end := start
+			for end+1 < len(tags) && tags[end+1].index == tags[end].index+1 && sameValue(tags[end+1].binAnno, tags[start].binAnno) {
+				end++
+			}
+			if end > start {
+				tags[start].binAnno.Key = prefix + "." + strconv.Itoa(tags[start].index) + "-" + strconv.Itoa(tags[end].index)
+				for _, tag := range tags[start+1 : end+1] {
+					removed[tag.binAnno] = struct{}{}
+				}
+			}
+			start = end + 1
+		}
+	}
+	if len(removed) == 0 {
+		return span, nil
+	}
+	binAnnos := make([]*zc.BinaryAnnotation, 0, len(span.BinaryAnnotations)-len(removed))
+	for _, binAnno := range span.BinaryAnnotations {
+		if _, ok := removed[binAnno]; !ok {
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	span.BinaryAnnotations = binAnnos
+	appendStringTag(span, collapsedIndexedTagsTag, strconv.Itoa(len(removed)))
+	return span, nil
+}
+
+func sameValue(a, b *zc.BinaryAnnotation) bool {
+	return a.AnnotationType == b.AnnotationType && bytes.Equal(a.Value, b.Value)
+}
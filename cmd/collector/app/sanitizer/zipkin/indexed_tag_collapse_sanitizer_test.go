@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestIndexedTagCollapseSanitizer(t *testing.T) {
+	sanitizer := NewIndexedTagCollapseSanitizer([]string{"arg"})
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("arg.0", "x"),
+			stringTag("arg.2", "x"),
+			stringTag("component", "rpc"),
+			stringTag("arg.1", "x"),
+			stringTag("arg.3", "y"),
+			stringTag("arg.4", "x"),
+			stringTag("arg.6", "x"),
+			stringTag("argument.7", "x"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("arg.0-2", "x"),
+		stringTag("component", "rpc"),
+		stringTag("arg.3", "y"),
+		stringTag("arg.4", "x"),
+		stringTag("arg.6", "x"),
+		stringTag("argument.7", "x"),
+		stringTag(collapsedIndexedTagsTag, "2"),
+	}, span.BinaryAnnotations)
+}
+
+func TestIndexedTagCollapseSanitizerDistinctValues(t *testing.T) {
+	sanitizer := NewIndexedTagCollapseSanitizer([]string{"arg"})
+	tags := []*zc.BinaryAnnotation{
+		stringTag("arg.0", "x"),
+		{Key: "arg.1", Value: []byte("x"), AnnotationType: zc.AnnotationType_BYTES},
+		stringTag("arg.2", "z"),
+	}
+	span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: tags})
+	require.NoError(t, err)
+	assert.Equal(t, tags, span.BinaryAnnotations)
+}
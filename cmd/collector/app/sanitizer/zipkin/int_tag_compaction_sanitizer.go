@@ -0,0 +1,65 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const compactedIntTag = "warnCompactedIntTag"
+
+// NewIntTagCompactionSanitizer returns a sanitizer that converts the STRING tags with the given keys holding
+// decimal integers, e.g. 'http.request_content_length', into I64 tags. Values that are not integers or
+// overflow int64 are left as STRING. The keys of the converted tags are recorded in a 'warnCompactedIntTag' tag.
+func NewIntTagCompactionSanitizer(keys []string) Sanitizer {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[key] = struct{}{}
+	}
+	return &intTagCompactionSanitizer{keys: keySet}
+}
+
+type intTagCompactionSanitizer struct {
+	keys map[string]struct{}
+}
+
+func (s *intTagCompactionSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var compacted []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if _, ok := s.keys[binAnno.Key]; !ok || binAnno.AnnotationType != zc.AnnotationType_STRING {
+			continue
+		}
+		value, err := strconv.ParseInt(string(binAnno.Value), 10, 64)
+		if err != nil {
+			continue
+		}
+		binAnno.AnnotationType = zc.AnnotationType_I64
+		binAnno.Value = int64Bytes(value)
+		compacted = append(compacted, binAnno.Key)
+	}
+	if len(compacted) > 0 {
+		appendStringTag(span, compactedIntTag, strings.Join(compacted, ","))
+	}
+	return span, nil
+}
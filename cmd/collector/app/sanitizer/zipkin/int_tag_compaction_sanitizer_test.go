@@ -0,0 +1,59 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestIntTagCompactionSanitizer(t *testing.T) {
+	sanitizer := NewIntTagCompactionSanitizer([]string{"http.request_content_length", "http.response_content_length", "retries"})
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("http.request_content_length", "-1048576"),
+			stringTag("http.response_content_length", "9223372036854775808"),
+			stringTag("retries", "three"),
+			stringTag("other", "42"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		{Key: "http.request_content_length", Value: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xf0, 0, 0}, AnnotationType: zc.AnnotationType_I64},
+		stringTag("http.response_content_length", "9223372036854775808"),
+		stringTag("retries", "three"),
+		stringTag("other", "42"),
+		stringTag(compactedIntTag, "http.request_content_length"),
+	}, span.BinaryAnnotations)
+}
+
+func TestIntTagCompactionSanitizerNoop(t *testing.T) {
+	sanitizer := NewIntTagCompactionSanitizer([]string{"retries"})
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("retries", " 3")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag("retries", " 3")}, span.BinaryAnnotations)
+}
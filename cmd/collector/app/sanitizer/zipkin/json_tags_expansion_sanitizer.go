@@ -0,0 +1,117 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	expandedJSONTagsTag = "warnExpandedJSONTags"
+	badJSONTagsTag      = "warnBadJSONTags"
+)
+
+// NewJSONTagsExpansionSanitizer returns a sanitizer that expands a STRING tag holding a JSON object of tags,
+// as sent by some exporters, into discrete binary annotations. Strings, integers, other numbers and booleans
+// become STRING, I64, DOUBLE and BOOL annotations respectively, nested objects are flattened into dotted keys,
+// arrays are kept as their JSON text and nulls are skipped. The source tag is removed and the number of
+// expanded tags is recorded in a 'warnExpandedJSONTags' tag. A source tag that is not a JSON object is left
+// intact and flagged with a 'warnBadJSONTags' tag.
+func NewJSONTagsExpansionSanitizer(sourceKey string) Sanitizer {
+	return &jsonTagsExpansionSanitizer{sourceKey: sourceKey}
+}
+
+type jsonTagsExpansionSanitizer struct {
+	sourceKey string
+}
+
+func (s *jsonTagsExpansionSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	source := findBinaryAnnotation(span, s.sourceKey)
+	if source == nil || source.AnnotationType != zc.AnnotationType_STRING {
+		return span, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(source.Value))
+	decoder.UseNumber()
+	var tags map[string]interface{}
+	if err := decoder.Decode(&tags); err != nil || tags == nil || decoder.More() {
+		appendStringTag(span, badJSONTagsTag, s.sourceKey)
+		return span, nil
+	}
+	var expanded []*zc.BinaryAnnotation
+	expandJSONTags(&expanded, "", tags, source.Host)
+	removeBinaryAnnotations(span, s.sourceKey)
+	span.BinaryAnnotations = append(span.BinaryAnnotations, expanded...)
+	appendStringTag(span, expandedJSONTagsTag, strconv.Itoa(len(expanded)))
+	return span, nil
+}
+
+// expandJSONTags appends a binary annotation for each value of the object, in key order,
+// flattening nested objects with the prefix.
+func expandJSONTags(binAnnos *[]*zc.BinaryAnnotation, prefix string, object map[string]interface{}, host *zc.Endpoint) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		binAnno := &zc.BinaryAnnotation{Key: prefix + key, Host: host}
+		switch value := object[key].(type) {
+		case map[string]interface{}:
+			expandJSONTags(binAnnos, prefix+key+".", value, host)
+			continue
+		case string:
+			binAnno.AnnotationType = zc.AnnotationType_STRING
+			binAnno.Value = []byte(value)
+		case bool:
+			binAnno.AnnotationType = zc.AnnotationType_BOOL
+			binAnno.Value = []byte{0}
+			if value {
+				binAnno.Value = []byte{1}
+			}
+		case json.Number:
+			if i, err := value.Int64(); err == nil {
+				binAnno.AnnotationType = zc.AnnotationType_I64
+				binAnno.Value = int64Bytes(i)
+			} else if f, err := value.Float64(); err == nil {
+				binAnno.AnnotationType = zc.AnnotationType_DOUBLE
+				binAnno.Value = float64Bytes(f)
+			} else {
+				binAnno.AnnotationType = zc.AnnotationType_STRING
+				binAnno.Value = []byte(value.String())
+			}
+		case []interface{}:
+			text, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			binAnno.AnnotationType = zc.AnnotationType_STRING
+			binAnno.Value = text
+		default:
+			continue
+		}
+		*binAnnos = append(*binAnnos, binAnno)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestJSONTagsExpansionSanitizerFlat(t *testing.T) {
+	span, err := NewJSONTagsExpansionSanitizer("tags").Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("component", "http"),
+			stringTag("tags", `{"user": "bob", "retries": 3, "ratio": 0.5, "cached": true, "missing": null}`),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("component", "http"),
+		{Key: "cached", Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL},
+		{Key: "ratio", Value: float64Bytes(0.5), AnnotationType: zc.AnnotationType_DOUBLE},
+		{Key: "retries", Value: int64Bytes(3), AnnotationType: zc.AnnotationType_I64},
+		stringTag("user", "bob"),
+		stringTag(expandedJSONTagsTag, "4"),
+	}, span.BinaryAnnotations)
+}
+
+func TestJSONTagsExpansionSanitizerNested(t *testing.T) {
+	span, err := NewJSONTagsExpansionSanitizer("tags").Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("tags", `{"db": {"type": "sql", "pool": {"size": 10}}, "ids": [1, 2]}`),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		{Key: "db.pool.size", Value: int64Bytes(10), AnnotationType: zc.AnnotationType_I64},
+		stringTag("db.type", "sql"),
+		stringTag("ids", "[1,2]"),
+		stringTag(expandedJSONTagsTag, "3"),
+	}, span.BinaryAnnotations)
+}
+
+func TestJSONTagsExpansionSanitizerMalformed(t *testing.T) {
+	for _, value := range []string{`{"user": `, `["user"]`, `null`, `{} {}`} {
+		span, err := NewJSONTagsExpansionSanitizer("tags").Sanitize(&zc.Span{
+			BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("tags", value)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*zc.BinaryAnnotation{
+			stringTag("tags", value),
+			stringTag(badJSONTagsTag, "tags"),
+		}, span.BinaryAnnotations, value)
+	}
+}
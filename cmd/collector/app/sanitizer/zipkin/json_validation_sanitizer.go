@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"encoding/json"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const invalidJSONTag = "warnInvalidJSON"
+
+// NewJSONValidationSanitizer returns a sanitizer that guarantees that the STRING tags with the given keys,
+// e.g. 'request.body', hold valid JSON. Invalid values are wrapped as a JSON string, and their keys are
+// recorded in a 'warnInvalidJSON' tag. Valid values are left byte for byte untouched.
+func NewJSONValidationSanitizer(keys []string) Sanitizer {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[key] = struct{}{}
+	}
+	return &jsonValidationSanitizer{keys: keySet}
+}
+
+type jsonValidationSanitizer struct {
+	keys map[string]struct{}
+}
+
+func (s *jsonValidationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var wrapped []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if _, ok := s.keys[binAnno.Key]; !ok || binAnno.AnnotationType != zc.AnnotationType_STRING {
+			continue
+		}
+		var raw json.RawMessage
+		if err := json.Unmarshal(binAnno.Value, &raw); err == nil {
+			continue
+		}
+		value, err := json.Marshal(string(binAnno.Value))
+		if err != nil {
+			continue
+		}
+		binAnno.Value = value
+		wrapped = append(wrapped, binAnno.Key)
+	}
+	if len(wrapped) > 0 {
+		appendStringTag(span, invalidJSONTag, strings.Join(wrapped, ","))
+	}
+	return span, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestJSONValidationSanitizer(t *testing.T) {
+	sanitizer := NewJSONValidationSanitizer([]string{"request.body", "response.body"})
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("request.body", `{"b": 1, "a": [true, null]}`),
+			stringTag("response.body", `{"error": "oops`),
+			stringTag("other.body", `not json`),
+		},
+	})
+	require.NoError(t, err)
+	expected := []*zc.BinaryAnnotation{
+		stringTag("request.body", `{"b": 1, "a": [true, null]}`),
+		stringTag("response.body", `"{\"error\": \"oops"`),
+		stringTag("other.body", `not json`),
+		stringTag(invalidJSONTag, "response.body"),
+	}
+	assert.Equal(t, expected, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, expected, span.BinaryAnnotations)
+}
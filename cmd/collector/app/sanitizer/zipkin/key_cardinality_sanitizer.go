@@ -0,0 +1,67 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const keyCardinalityExceededTag = "warnKeyCardinalityExceeded"
+
+// NewKeyCardinalitySanitizer returns a sanitizer that limits the number of distinct tag keys of a span.
+// The first maxDistinctKeys keys, in order of appearance, are kept and the tags with other keys are dropped.
+// Tags added by sanitizers are exempt. The number of dropped tags is recorded in a 'warnKeyCardinalityExceeded' tag.
+func NewKeyCardinalitySanitizer(maxDistinctKeys int) Sanitizer {
+	return &keyCardinalitySanitizer{maxDistinctKeys: maxDistinctKeys}
+}
+
+type keyCardinalitySanitizer struct {
+	maxDistinctKeys int
+}
+
+func (s *keyCardinalitySanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if len(span.BinaryAnnotations) <= s.maxDistinctKeys {
+		return span, nil
+	}
+	keys := make(map[string]struct{}, s.maxDistinctKeys)
+	binAnnos := make([]*zc.BinaryAnnotation, 0, len(span.BinaryAnnotations))
+	dropped := 0
+	for _, binAnno := range span.BinaryAnnotations {
+		if !isSanitizerTag(binAnno.Key) {
+			if _, ok := keys[binAnno.Key]; !ok {
+				if len(keys) == s.maxDistinctKeys {
+					dropped++
+					continue
+				}
+				keys[binAnno.Key] = struct{}{}
+			}
+		}
+		binAnnos = append(binAnnos, binAnno)
+	}
+	if dropped == 0 {
+		return span, nil
+	}
+	span.BinaryAnnotations = binAnnos
+	appendStringTag(span, keyCardinalityExceededTag, strconv.Itoa(dropped))
+	return span, nil
+}
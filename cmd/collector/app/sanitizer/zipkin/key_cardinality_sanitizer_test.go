@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestKeyCardinalitySanitizer(t *testing.T) {
+	sanitizer := NewKeyCardinalitySanitizer(2)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("a", "1"),
+			stringTag("b", "1"),
+			stringTag("c", "1"),
+			stringTag("a", "2"),
+			stringTag(negativeDurationTag, "-1"),
+			stringTag("error", "true"),
+			stringTag("d", "1"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("a", "1"),
+		stringTag("b", "1"),
+		stringTag("a", "2"),
+		stringTag(negativeDurationTag, "-1"),
+		stringTag(keyCardinalityExceededTag, "3"),
+	}, span.BinaryAnnotations)
+}
+
+func TestKeyCardinalitySanitizerUnderLimit(t *testing.T) {
+	tags := []*zc.BinaryAnnotation{stringTag("a", "1"), stringTag("a", "2"), stringTag("b", "1")}
+	span, err := NewKeyCardinalitySanitizer(2).Sanitize(&zc.Span{BinaryAnnotations: tags})
+	require.NoError(t, err)
+	assert.Equal(t, tags, span.BinaryAnnotations)
+}
+
+func TestIsSanitizerTag(t *testing.T) {
+	for key, expected := range map[string]bool{
+		negativeDurationTag: true,
+		reprocessedTag:      true,
+		sanitizedStampTag:   true,
+		"error":             false,
+		"err":               false,
+		"warning":           false,
+		"http.method":       false,
+	} {
+		assert.Equal(t, expected, isSanitizerTag(key), key)
+	}
+}
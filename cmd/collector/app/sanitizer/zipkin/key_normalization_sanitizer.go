@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewKeyNormalizationSanitizer returns a sanitizer that lowercases and trims the whitespace around binary
+// annotation keys, so that e.g. 'HTTP.Method' and ' http.method' are both searchable as 'http.method'.
+// When different keys normalize to the same key, all the binary annotations are kept and a warning is logged.
+func NewKeyNormalizationSanitizer(logger *zap.Logger) Sanitizer {
+	return &keyNormalizationSanitizer{log: spanLogger{logger}}
+}
+
+type keyNormalizationSanitizer struct {
+	log spanLogger
+}
+
+func (s *keyNormalizationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	originalKeys := make(map[string]string, len(span.BinaryAnnotations))
+	for _, binAnno := range span.BinaryAnnotations {
+		key := strings.ToLower(strings.TrimSpace(binAnno.Key))
+		if original, ok := originalKeys[key]; !ok {
+			originalKeys[key] = binAnno.Key
+		} else if original != binAnno.Key {
+			s.log.ForSpan(span).Warn("Tag keys collide after normalization",
+				zap.String("key", key), zap.String("first", original), zap.String("second", binAnno.Key))
+		}
+		binAnno.Key = key
+	}
+	return span, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	canonicalizedLibraryVersionTag = "warnCanonicalizedLibraryVersion"
+	badVersionTag                  = "warnBadVersion"
+)
+
+var (
+	// libraryVersionKeys lists the tags holding the instrumentation library version, canonical key first.
+	libraryVersionKeys = []string{"otel.library.version", "component.version", "library.version"}
+
+	semverPattern = regexp.MustCompile(`^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+)
+
+// NewLibraryVersionSanitizer returns a sanitizer that consolidates the 'component.version' and 'library.version'
+// string tags into the canonical 'otel.library.version' tag. The first semver-valid value is kept, falling back
+// to the first non-empty value. The values that are not valid semver are recorded in a 'warnBadVersion' tag.
+func NewLibraryVersionSanitizer() Sanitizer {
+	return &libraryVersionSanitizer{}
+}
+
+type libraryVersionSanitizer struct {
+}
+
+func (s *libraryVersionSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var badVersions []string
+	merged := mergeStringTags(span, libraryVersionKeys, func(candidates []*zc.BinaryAnnotation) *zc.BinaryAnnotation {
+		var valid, nonEmpty *zc.BinaryAnnotation
+		for _, candidate := range candidates {
+			if len(candidate.Value) == 0 {
+				continue
+			}
+			if !semverPattern.Match(candidate.Value) {
+				badVersions = append(badVersions, string(candidate.Value))
+			} else if valid == nil {
+				valid = candidate
+			}
+			if nonEmpty == nil {
+				nonEmpty = candidate
+			}
+		}
+		if valid != nil {
+			return valid
+		}
+		return nonEmpty
+	})
+	if merged != nil {
+		appendStringTag(span, canonicalizedLibraryVersionTag, strings.Join(merged, ","))
+	}
+	if len(badVersions) > 0 && findBinaryAnnotation(span, badVersionTag) == nil {
+		appendStringTag(span, badVersionTag, strings.Join(badVersions, ","))
+	}
+	return span, nil
+}
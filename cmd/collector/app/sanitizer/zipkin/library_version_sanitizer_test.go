@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestLibraryVersionSanitizer(t *testing.T) {
+	tests := []struct {
+		tags     []*zc.BinaryAnnotation
+		expected []*zc.BinaryAnnotation
+	}{
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("library.version", "1.2"),
+				stringTag("component.version", "v1.2.3"),
+				stringTag("otel.library.version", ""),
+			},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("otel.library.version", "v1.2.3"),
+				stringTag(canonicalizedLibraryVersionTag, "library.version,component.version,otel.library.version"),
+				stringTag(badVersionTag, "1.2"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("component.version", "latest"),
+			},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("otel.library.version", "latest"),
+				stringTag(canonicalizedLibraryVersionTag, "component.version"),
+				stringTag(badVersionTag, "latest"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag("otel.library.version", "2.0.0-rc.1+build.5"),
+			},
+			expected: []*zc.BinaryAnnotation{
+				stringTag("otel.library.version", "2.0.0-rc.1+build.5"),
+			},
+		},
+	}
+	sanitizer := NewLibraryVersionSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: test.tags})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations)
+	}
+}
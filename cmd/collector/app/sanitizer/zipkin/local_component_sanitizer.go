@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const componentKey = "component"
+
+// NewLocalComponentSanitizer returns a sanitizer that renames the 'lc' (local component) binary annotation
+// of legacy Zipkin clients to 'component'. If the span already has a 'component' binary annotation, both
+// are kept and a warning is logged.
+func NewLocalComponentSanitizer(logger *zap.Logger) Sanitizer {
+	return &localComponentSanitizer{log: spanLogger{logger}}
+}
+
+type localComponentSanitizer struct {
+	log spanLogger
+}
+
+func (s *localComponentSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	lc := findBinaryAnnotation(span, zc.LOCAL_COMPONENT)
+	if lc == nil {
+		return span, nil
+	}
+	if component := findBinaryAnnotation(span, componentKey); component != nil {
+		s.log.ForSpan(span).Warn("Span has both local component and component tags",
+			zap.String("lc", string(lc.Value)), zap.String("component", string(component.Value)))
+		return span, nil
+	}
+	lc.Key = componentKey
+	return span, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestLocalComponentSanitizer(t *testing.T) {
+	tests := []struct {
+		input    []*zc.BinaryAnnotation
+		expected []*zc.BinaryAnnotation
+		warnings int
+		descr    string
+	}{
+		{
+			input:    []*zc.BinaryAnnotation{stringTag("lc", "cache"), stringTag("key", "value")},
+			expected: []*zc.BinaryAnnotation{stringTag("component", "cache"), stringTag("key", "value")},
+			descr:    "lc only",
+		},
+		{
+			input:    []*zc.BinaryAnnotation{stringTag("component", "grpc")},
+			expected: []*zc.BinaryAnnotation{stringTag("component", "grpc")},
+			descr:    "component only",
+		},
+		{
+			input:    []*zc.BinaryAnnotation{stringTag("component", "grpc"), stringTag("lc", "cache")},
+			expected: []*zc.BinaryAnnotation{stringTag("component", "grpc"), stringTag("lc", "cache")},
+			warnings: 1,
+			descr:    "both",
+		},
+	}
+	for _, test := range tests {
+		logger, log := testutils.NewLogger()
+		span, err := NewLocalComponentSanitizer(logger).Sanitize(&zc.Span{BinaryAnnotations: test.input})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations, test.descr)
+		assert.Len(t, log.Lines(), test.warnings, test.descr)
+	}
+}
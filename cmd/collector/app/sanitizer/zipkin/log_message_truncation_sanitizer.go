@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const truncatedLogMessageTag = "warnTruncatedLogMessage"
+
+// NewLogMessageTruncationSanitizer returns a sanitizer that truncates annotation values longer than
+// maxLen runes, ending them with an ellipsis. Core annotations are exempt since their values carry semantics.
+// The number of truncated annotations is recorded in a 'warnTruncatedLogMessage' tag.
+func NewLogMessageTruncationSanitizer(maxLen int) Sanitizer {
+	return &logMessageTruncationSanitizer{maxLen: maxLen}
+}
+
+type logMessageTruncationSanitizer struct {
+	maxLen int
+}
+
+func (s *logMessageTruncationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	truncated := 0
+	for _, anno := range span.Annotations {
+		if isCoreAnnotation(anno) {
+			continue
+		}
+		if value, ok := truncateRunes(anno.Value, s.maxLen); ok {
+			anno.Value = value
+			truncated++
+		}
+	}
+	if truncated > 0 {
+		appendStringTag(span, truncatedLogMessageTag, strconv.Itoa(truncated))
+	}
+	return span, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func annotationValues(span *zc.Span) []string {
+	values := make([]string, len(span.Annotations))
+	for i, anno := range span.Annotations {
+		values[i] = anno.Value
+	}
+	return values
+}
+
+func TestLogMessageTruncationSanitizer(t *testing.T) {
+	sanitizer := NewLogMessageTruncationSanitizer(6)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.SERVER_RECV},
+			{Value: "connection réset by peer"},
+			{Value: "retry"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{zc.SERVER_RECV, "conne…", "retry"}, annotationValues(span))
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(truncatedLogMessageTag, "1")}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, []string{zc.SERVER_RECV, "conne…", "retry"}, annotationValues(span))
+	assert.Len(t, span.BinaryAnnotations, 1)
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		value     string
+		maxLen    int
+		expected  string
+		truncated bool
+	}{
+		{value: "abc", maxLen: 3, expected: "abc"},
+		{value: "abcd", maxLen: 3, expected: "ab…", truncated: true},
+		{value: "éééé", maxLen: 2, expected: "é…", truncated: true},
+		{value: "abcd", maxLen: 1, expected: "…", truncated: true},
+		{value: "abcd", maxLen: 0, expected: "", truncated: true},
+	}
+	for _, test := range tests {
+		value, truncated := truncateRunes(test.value, test.maxLen)
+		assert.Equal(t, test.expected, value)
+		assert.Equal(t, test.truncated, truncated)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const spanTooLargeTag = "errSpanTooLarge"
+
+// NewMaxSpanSizeSanitizer returns a sanitizer that limits the size of a span to maxBytes. The size is
+// estimated as the length of the span name, of the annotation values and of the binary annotation keys
+// and values. Over budget, the largest binary annotations are dropped, the later first among equally
+// large ones, until the span fits, and the original estimated size is recorded in an 'errSpanTooLarge' tag.
+// Tags added by other sanitizers are never dropped.
+func NewMaxSpanSizeSanitizer(maxBytes int, logger *zap.Logger) Sanitizer {
+	return &maxSpanSizeSanitizer{log: spanLogger{logger}, maxBytes: maxBytes}
+}
+
+type maxSpanSizeSanitizer struct {
+	log      spanLogger
+	maxBytes int
+}
+
+func (s *maxSpanSizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	size := len(span.Name)
+	for _, anno := range span.Annotations {
+		size += len(anno.Value)
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		size += binaryAnnotationSize(binAnno)
+	}
+	if size <= s.maxBytes {
+		return span, nil
+	}
+	candidates := binaryAnnotationsBySizeDesc{binaryAnnotations: span.BinaryAnnotations}
+	for i, binAnno := range span.BinaryAnnotations {
+		if !isSanitizerTag(binAnno.Key) {
+			candidates.indices = append(candidates.indices, i)
+		}
+	}
+	sort.Sort(candidates)
+	drop := make(map[int]struct{})
+	remaining := size
+	for _, i := range candidates.indices {
+		if remaining <= s.maxBytes {
+			break
+		}
+		remaining -= binaryAnnotationSize(span.BinaryAnnotations[i])
+		drop[i] = struct{}{}
+	}
+	binAnnos := make([]*zc.BinaryAnnotation, 0, len(span.BinaryAnnotations)-len(drop)+1)
+	for i, binAnno := range span.BinaryAnnotations {
+		if _, ok := drop[i]; !ok {
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	s.log.ForSpan(span).Debug("Span too large", zap.Int("size", size), zap.Int("dropped", len(drop)))
+	span.BinaryAnnotations = binAnnos
+	appendStringTag(span, spanTooLargeTag, strconv.Itoa(size))
+	return span, nil
+}
+
+// binaryAnnotationSize returns the estimated size of a binary annotation, the length of its key and value.
+func binaryAnnotationSize(binAnno *zc.BinaryAnnotation) int {
+	return len(binAnno.Key) + len(binAnno.Value)
+}
+
+// binaryAnnotationsBySizeDesc sorts binary annotation indices by decreasing size, and by decreasing index
+// for equal sizes.
+type binaryAnnotationsBySizeDesc struct {
+	binaryAnnotations []*zc.BinaryAnnotation
+	indices           []int
+}
+
+func (s binaryAnnotationsBySizeDesc) Len() int { return len(s.indices) }
+func (s binaryAnnotationsBySizeDesc) Swap(i, j int) {
+	s.indices[i], s.indices[j] = s.indices[j], s.indices[i]
+}
+func (s binaryAnnotationsBySizeDesc) Less(i, j int) bool {
+	a, b := binaryAnnotationSize(s.binaryAnnotations[s.indices[i]]), binaryAnnotationSize(s.binaryAnnotations[s.indices[j]])
+	if a != b {
+		return a > b
+	}
+	return s.indices[i] > s.indices[j]
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// sizedSpan returns a span of estimated size 119: a 10 bytes name, a 10 bytes annotation value and
+// binary annotations of 20, 40, 20 and 19 bytes, the last one being a sanitizer tag.
+func sizedSpan() *zc.Span {
+	return &zc.Span{
+		Name:        strings.Repeat("n", 10),
+		Annotations: []*zc.Annotation{{Value: strings.Repeat("a", 10)}},
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("k1", strings.Repeat("v", 18)),
+			stringTag("k2", strings.Repeat("v", 38)),
+			stringTag("k3", strings.Repeat("v", 18)),
+			stringTag(negativeDurationTag, ""),
+		},
+	}
+}
+
+func TestMaxSpanSizeSanitizer(t *testing.T) {
+	binAnnos := sizedSpan().BinaryAnnotations
+	tests := []struct {
+		maxBytes int
+		expected []*zc.BinaryAnnotation
+	}{
+		{maxBytes: 120, expected: binAnnos},
+		{maxBytes: 119, expected: binAnnos},
+		{
+			maxBytes: 118,
+			expected: []*zc.BinaryAnnotation{binAnnos[0], binAnnos[2], binAnnos[3], stringTag(spanTooLargeTag, "119")},
+		},
+		{
+			maxBytes: 79,
+			expected: []*zc.BinaryAnnotation{binAnnos[0], binAnnos[2], binAnnos[3], stringTag(spanTooLargeTag, "119")},
+		},
+		{
+			maxBytes: 78,
+			expected: []*zc.BinaryAnnotation{binAnnos[0], binAnnos[3], stringTag(spanTooLargeTag, "119")},
+		},
+		{
+			maxBytes: 0,
+			expected: []*zc.BinaryAnnotation{binAnnos[3], stringTag(spanTooLargeTag, "119")},
+		},
+	}
+	for _, test := range tests {
+		sanitizer := NewMaxSpanSizeSanitizer(test.maxBytes, zap.NewNop())
+		span, err := sanitizer.Sanitize(sizedSpan())
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations, "maxBytes %d", test.maxBytes)
+		assert.Equal(t, strings.Repeat("n", 10), span.Name)
+		assert.Len(t, span.Annotations, 1)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const tooManyTagsTag = "errTooManyTags"
+
+// NewMaxTagCountSanitizer returns a sanitizer that limits the number of binary annotations of a span to max.
+// The first max binary annotations are kept in their original order and the rest are dropped. The number
+// of dropped binary annotations is recorded in an 'errTooManyTags' tag, which is not counted against max.
+// A negative max disables the sanitizer.
+func NewMaxTagCountSanitizer(logger *zap.Logger, max int) Sanitizer {
+	if max < 0 {
+		return NewNoopSanitizer()
+	}
+	return &maxTagCountSanitizer{log: spanLogger{logger}, max: max}
+}
+
+type maxTagCountSanitizer struct {
+	log spanLogger
+	max int
+}
+
+func (s *maxTagCountSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if len(span.BinaryAnnotations) <= s.max {
+		return span, nil
+	}
+	dropped := len(span.BinaryAnnotations) - s.max
+	s.log.ForSpan(span).Debug("Too many tags", zap.Int("dropped", dropped))
+	// copy the kept tags instead of reslicing so that the dropped ones can be garbage collected
+	binAnnos := make([]*zc.BinaryAnnotation, s.max, s.max+1)
+	copy(binAnnos, span.BinaryAnnotations)
+	span.BinaryAnnotations = binAnnos
+	appendStringTag(span, tooManyTagsTag, strconv.Itoa(dropped))
+	return span, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func tagsSpan(n int) *zc.Span {
+	span := &zc.Span{}
+	for i := 0; i < n; i++ {
+		span.BinaryAnnotations = append(span.BinaryAnnotations, stringTag("key-"+strconv.Itoa(i), "value"))
+	}
+	return span
+}
+
+func TestMaxTagCountSanitizer(t *testing.T) {
+	tests := []struct {
+		count    int
+		expected int
+		dropped  string
+	}{
+		{count: 0, expected: 0},
+		{count: 3, expected: 3},
+		{count: 4, expected: 3, dropped: "1"},
+		{count: 10, expected: 3, dropped: "7"},
+	}
+	sanitizer := NewMaxTagCountSanitizer(zap.NewNop(), 3)
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(tagsSpan(test.count))
+		require.NoError(t, err)
+		expected := tagsSpan(test.expected).BinaryAnnotations
+		if test.dropped != "" {
+			expected = append(expected, stringTag(tooManyTagsTag, test.dropped))
+		}
+		assert.Equal(t, expected, span.BinaryAnnotations)
+	}
+}
+
+func TestMaxTagCountSanitizerNegative(t *testing.T) {
+	assert.Equal(t, NewNoopSanitizer(), NewMaxTagCountSanitizer(zap.NewNop(), -1))
+}
+
+// BenchmarkMaxTagCountSanitizer 	  808436	      1268 ns/op	    1424 B/op	       9 allocs/op
+func BenchmarkMaxTagCountSanitizer(b *testing.B) {
+	binAnnos := tagsSpan(10000).BinaryAnnotations
+	sanitizer := NewMaxTagCountSanitizer(zap.NewNop(), 100)
+	span := &zc.Span{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		span.BinaryAnnotations = binAnnos
+		sanitizer.Sanitize(span)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	truncatedTagPrefix   = "error.truncated."
+	truncatedValueSuffix = "...[truncated]"
+)
+
+// NewMaxTagValueLengthSanitizer returns a sanitizer that truncates STRING and BYTES binary annotation values
+// longer than maxBytes. STRING values are cut at a UTF-8 character boundary and end with '...[truncated]',
+// which counts against maxBytes unless maxBytes is too small to hold it, BYTES values are cut without a suffix.
+// The original length of each truncated value is recorded in an 'error.truncated.<key>' tag, unless the span
+// already has one. Numeric values are never longer than 8 bytes and are left alone. A negative maxBytes
+// disables the sanitizer.
+func NewMaxTagValueLengthSanitizer(logger *zap.Logger, maxBytes int) Sanitizer {
+	if maxBytes < 0 {
+		return NewNoopSanitizer()
+	}
+	return &maxTagValueLengthSanitizer{log: spanLogger{logger}, maxBytes: maxBytes}
+}
+
+type maxTagValueLengthSanitizer struct {
+	log      spanLogger
+	maxBytes int
+}
+
+func (s *maxTagValueLengthSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	for _, binAnno := range span.BinaryAnnotations {
+		if len(binAnno.Value) <= s.maxBytes {
+			continue
+		}
+		var value []byte
+		switch binAnno.AnnotationType {
+		case zc.AnnotationType_STRING:
+			if s.maxBytes < len(truncatedValueSuffix) {
+				value = []byte(truncateUTF8(string(binAnno.Value), s.maxBytes))
+			} else {
+				value = []byte(truncateUTF8(string(binAnno.Value), s.maxBytes-len(truncatedValueSuffix)) + truncatedValueSuffix)
+			}
+		case zc.AnnotationType_BYTES:
+			// copy the kept bytes instead of reslicing so that the original value can be garbage collected
+			value = make([]byte, s.maxBytes)
+			copy(value, binAnno.Value)
+		default:
+			continue
+		}
+		s.log.ForSpan(span).Debug("Truncated tag value", zap.String("key", binAnno.Key), zap.Int("length", len(binAnno.Value)))
+		if findBinaryAnnotation(span, truncatedTagPrefix+binAnno.Key) == nil {
+			appendStringTag(span, truncatedTagPrefix+binAnno.Key, strconv.Itoa(len(binAnno.Value)))
+		}
+		binAnno.Value = value
+	}
+	return span, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestMaxTagValueLengthSanitizer(t *testing.T) {
+	tests := []struct {
+		annoType  zc.AnnotationType
+		value     string
+		expected  string
+		truncated string
+	}{
+		{annoType: zc.AnnotationType_STRING, value: "abcdefghijklmnopqr", expected: "abcdefghijklmnopqr"},
+		{annoType: zc.AnnotationType_STRING, value: "abcdefghijklmnopqrs", expected: "abcd...[truncated]", truncated: "19"},
+		{annoType: zc.AnnotationType_STRING, value: "abcéfghijklmnopqrs", expected: "abc...[truncated]", truncated: "19"},
+		{annoType: zc.AnnotationType_BYTES, value: "abcdefghijklmnopqr", expected: "abcdefghijklmnopqr"},
+		{annoType: zc.AnnotationType_BYTES, value: "abcdefghijklmnopqrst", expected: "abcdefghijklmnopqr", truncated: "20"},
+		{annoType: zc.AnnotationType_I64, value: "\x00\x00\x00\x00\x00\x00\x00\x01", expected: "\x00\x00\x00\x00\x00\x00\x00\x01"},
+	}
+	sanitizer := NewMaxTagValueLengthSanitizer(zap.NewNop(), 18)
+	for _, test := range tests {
+		span := &zc.Span{BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: "payload", Value: []byte(test.value), AnnotationType: test.annoType},
+		}}
+		span, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, string(span.BinaryAnnotations[0].Value), test.value)
+		assert.Equal(t, test.annoType, span.BinaryAnnotations[0].AnnotationType)
+		if test.truncated == "" {
+			assert.Len(t, span.BinaryAnnotations, 1, test.value)
+		} else {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag("error.truncated.payload", test.truncated)}, span.BinaryAnnotations[1:], test.value)
+		}
+	}
+}
+
+func TestMaxTagValueLengthSanitizerShortLimit(t *testing.T) {
+	sanitizer := NewMaxTagValueLengthSanitizer(zap.NewNop(), 4)
+	span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("payload", "abcdef")}})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("payload", "abcd"),
+		stringTag("error.truncated.payload", "6"),
+	}, span.BinaryAnnotations)
+}
+
+func TestMaxTagValueLengthSanitizerIdempotent(t *testing.T) {
+	sanitizer := NewMaxTagValueLengthSanitizer(zap.NewNop(), 18)
+	span := &zc.Span{BinaryAnnotations: []*zc.BinaryAnnotation{
+		stringTag("payload", "abcdefghijklmnopqrstuvwxyz"),
+		{Key: "body", Value: make([]byte, 1000), AnnotationType: zc.AnnotationType_BYTES},
+	}}
+	for i := 0; i < 2; i++ {
+		var err error
+		span, err = sanitizer.Sanitize(span)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("payload", "abcd...[truncated]"),
+		{Key: "body", Value: make([]byte, 18), AnnotationType: zc.AnnotationType_BYTES},
+		stringTag("error.truncated.payload", "26"),
+		stringTag("error.truncated.body", "1000"),
+	}, span.BinaryAnnotations)
+	assert.Equal(t, 18, cap(span.BinaryAnnotations[1].Value))
+}
+
+func TestMaxTagValueLengthSanitizerNegative(t *testing.T) {
+	assert.Equal(t, NewNoopSanitizer(), NewMaxTagValueLengthSanitizer(zap.NewNop(), -1))
+}
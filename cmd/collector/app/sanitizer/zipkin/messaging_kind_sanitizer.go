@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	messagingKindInferredTag = "warnMessagingKindInferred"
+	messagingOperationKey    = "messaging.operation"
+)
+
+var messagingOperationKinds = map[string]string{
+	"publish": "producer",
+	"receive": "consumer",
+	"process": "consumer",
+}
+
+// NewMessagingKindSanitizer returns a sanitizer that infers the 'span.kind' tag of messaging spans from
+// their 'messaging.operation' tag, mapping 'publish' to 'producer', and 'receive' and 'process' to 'consumer'.
+// An explicit 'span.kind' is never overwritten and unknown operations are ignored. The inferred kind is
+// recorded in a 'warnMessagingKindInferred' tag.
+func NewMessagingKindSanitizer() Sanitizer {
+	return &messagingKindSanitizer{}
+}
+
+type messagingKindSanitizer struct {
+}
+
+func (s *messagingKindSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if findBinaryAnnotation(span, spanKindKey) != nil {
+		return span, nil
+	}
+	operation := findBinaryAnnotation(span, messagingOperationKey)
+	if operation == nil || operation.AnnotationType != zc.AnnotationType_STRING {
+		return span, nil
+	}
+	kind, ok := messagingOperationKinds[strings.ToLower(strings.TrimSpace(string(operation.Value)))]
+	if !ok {
+		return span, nil
+	}
+	appendStringTag(span, spanKindKey, kind)
+	appendStringTag(span, messagingKindInferredTag, kind)
+	return span, nil
+}
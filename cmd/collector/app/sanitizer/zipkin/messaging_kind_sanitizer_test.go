@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestMessagingKindSanitizer(t *testing.T) {
+	tests := []struct {
+		tags     []*zc.BinaryAnnotation
+		expected []*zc.BinaryAnnotation
+	}{
+		{
+			tags: []*zc.BinaryAnnotation{stringTag(messagingOperationKey, "publish")},
+			expected: []*zc.BinaryAnnotation{
+				stringTag(messagingOperationKey, "publish"),
+				stringTag(spanKindKey, "producer"),
+				stringTag(messagingKindInferredTag, "producer"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{stringTag(messagingOperationKey, "Receive")},
+			expected: []*zc.BinaryAnnotation{
+				stringTag(messagingOperationKey, "Receive"),
+				stringTag(spanKindKey, "consumer"),
+				stringTag(messagingKindInferredTag, "consumer"),
+			},
+		},
+		{
+			tags: []*zc.BinaryAnnotation{
+				stringTag(messagingOperationKey, "publish"),
+				stringTag(spanKindKey, "client"),
+			},
+			expected: []*zc.BinaryAnnotation{
+				stringTag(messagingOperationKey, "publish"),
+				stringTag(spanKindKey, "client"),
+			},
+		},
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag(messagingOperationKey, "settle")},
+			expected: []*zc.BinaryAnnotation{stringTag(messagingOperationKey, "settle")},
+		},
+	}
+	sanitizer := NewMessagingKindSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: test.tags})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations)
+	}
+}
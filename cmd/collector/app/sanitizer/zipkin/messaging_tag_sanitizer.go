@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	canonicalizedMessagingTag = "warnCanonicalizedMessagingTag"
+	messagingDestinationKey   = "messaging.destination"
+	peerServiceKey            = "peer.service"
+)
+
+// messagingDestinationKeys lists the tags holding the messaging destination, canonical key first.
+var messagingDestinationKeys = []string{messagingDestinationKey, "message_bus.destination"}
+
+// NewMessagingTagSanitizer returns a sanitizer that consolidates the 'message_bus.destination' string tag into
+// the canonical 'messaging.destination' tag, preferring non-empty values, and sets 'peer.service' to the
+// destination when the span has no 'peer.service' tag.
+func NewMessagingTagSanitizer() Sanitizer {
+	return &messagingTagSanitizer{}
+}
+
+type messagingTagSanitizer struct {
+}
+
+func (s *messagingTagSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	merged := mergeStringTags(span, messagingDestinationKeys, func(candidates []*zc.BinaryAnnotation) *zc.BinaryAnnotation {
+		var chosen *zc.BinaryAnnotation
+		for _, candidate := range candidates {
+			if len(candidate.Value) > 0 && (chosen == nil || keyRank(candidate, messagingDestinationKeys) < keyRank(chosen, messagingDestinationKeys)) {
+				chosen = candidate
+			}
+		}
+		return chosen
+	})
+	if merged != nil {
+		appendStringTag(span, canonicalizedMessagingTag, strings.Join(merged, ","))
+	}
+	destination := findBinaryAnnotation(span, messagingDestinationKey)
+	if destination != nil && len(destination.Value) > 0 && findBinaryAnnotation(span, peerServiceKey) == nil {
+		appendStringTag(span, peerServiceKey, string(destination.Value))
+	}
+	return span, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewMetricsSanitizer wraps a sanitizer to count its invocations, the spans it changed and the errors it
+// returned, and to time it, with metrics tagged with the given name. Changes are detected by comparing a cheap
+// signature of the span before and after sanitization, made of its name, parent ID, timestamp, duration and
+// annotation counts, so changes to annotation values alone are not counted.
+func NewMetricsSanitizer(name string, inner Sanitizer, factory metrics.Factory) Sanitizer {
+	tags := map[string]string{"sanitizer": name}
+	return &metricsSanitizer{
+		inner:       inner,
+		invocations: factory.Counter("sanitizer.invocations", tags),
+		changed:     factory.Counter("sanitizer.changed", tags),
+		errors:      factory.Counter("sanitizer.errors", tags),
+		latency:     factory.Timer("sanitizer.latency", tags),
+	}
+}
+
+// InstrumentedSanitizer is a ChainedSanitizer that records the time spent sanitizing each span.
+type InstrumentedSanitizer struct {
+	ChainedSanitizer
+	latency metrics.Timer
+}
+
+// NewInstrumentedSanitizer creates a chained sanitizer from the variadic list of passed Sanitizers, timing
+// the whole chain with a 'sanitizer.chain.latency' timer.
+func NewInstrumentedSanitizer(factory metrics.Factory, sanitizers ...Sanitizer) *InstrumentedSanitizer {
+	return &InstrumentedSanitizer{
+		ChainedSanitizer: NewChainedSanitizer(sanitizers...),
+		latency:          factory.Timer("sanitizer.chain.latency", nil),
+	}
+}
+
+// NewChainedSanitizerWithMetrics is like NewInstrumentedSanitizer, but also wraps each of the sanitizers with
+// NewMetricsSanitizer named after its type, e.g. 'spanDurationSanitizer'. The per-stage metrics cost a few
+// timestamps and counter updates per stage and span.
+func NewChainedSanitizerWithMetrics(factory metrics.Factory, sanitizers ...Sanitizer) *InstrumentedSanitizer {
+	instrumented := NewInstrumentedSanitizer(factory, sanitizers...)
+	for i, s := range instrumented.ChainedSanitizer {
+		instrumented.ChainedSanitizer[i] = NewMetricsSanitizer(sanitizerName(s), s, factory)
+	}
+	return instrumented
+}
+
+// Sanitize calls each Sanitize, returning the first error
+func (s *InstrumentedSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return s.SanitizeCtx(context.Background(), span)
+}
+
+// SanitizeCtx calls ChainedSanitizer.SanitizeCtx, recording the time it took
+func (s *InstrumentedSanitizer) SanitizeCtx(ctx context.Context, span *zc.Span) (*zc.Span, error) {
+	start := time.Now()
+	span, err := s.ChainedSanitizer.SanitizeCtx(ctx, span)
+	s.latency.Record(time.Since(start))
+	return span, err
+}
+
+type metricsSanitizer struct {
+	inner       Sanitizer
+	invocations metrics.Counter
+	changed     metrics.Counter
+	errors      metrics.Counter
+	latency     metrics.Timer
+}
+
+func (s *metricsSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return s.SanitizeCtx(context.Background(), span)
+}
+
+// SanitizeCtx passes the context on to the wrapped sanitizer if it implements ContextSanitizer.
+func (s *metricsSanitizer) SanitizeCtx(ctx context.Context, span *zc.Span) (*zc.Span, error) {
+	s.invocations.Inc(1)
+	before := newSpanSignature(span)
+	start := time.Now()
+	span, err := sanitizeCtx(ctx, s.inner, span)
+	s.latency.Record(time.Since(start))
+	if err != nil {
+		s.errors.Inc(1)
+		return span, err
+	}
+	if newSpanSignature(span) != before {
+		s.changed.Inc(1)
+	}
+	return span, nil
+}
+
+// spanSignature summarizes the parts of a span most sanitizers change.
+type spanSignature struct {
+	name              string
+	parentID          int64
+	hasParentID       bool
+	timestamp         int64
+	hasTimestamp      bool
+	duration          int64
+	hasDuration       bool
+	annotations       int
+	binaryAnnotations int
+}
+
+func newSpanSignature(span *zc.Span) spanSignature {
+	signature := spanSignature{
+		name:              span.Name,
+		annotations:       len(span.Annotations),
+		binaryAnnotations: len(span.BinaryAnnotations),
+	}
+	if span.ParentID != nil {
+		signature.parentID, signature.hasParentID = *span.ParentID, true
+	}
+	if span.Timestamp != nil {
+		signature.timestamp, signature.hasTimestamp = *span.Timestamp, true
+	}
+	if span.Duration != nil {
+		signature.duration, signature.hasDuration = *span.Duration, true
+	}
+	return signature
+}
+
+// sanitizerName returns the name of the type of the sanitizer, without package and pointer.
+func sanitizerName(s Sanitizer) string {
+	t := reflect.TypeOf(s)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestMetricsSanitizer(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	sanitizer := NewMetricsSanitizer("duration", NewSpanDurationSanitizer(zap.NewNop()), metricsFactory)
+
+	negative := int64(-1)
+	_, err := sanitizer.Sanitize(&zc.Span{Duration: &negative})
+	require.NoError(t, err)
+	positive := int64(10)
+	_, err = sanitizer.Sanitize(&zc.Span{Duration: &positive})
+	require.NoError(t, err)
+
+	counters, _ := metricsFactory.Snapshot()
+	assert.Equal(t, map[string]int64{
+		"sanitizer.invocations|sanitizer=duration": 2,
+		"sanitizer.changed|sanitizer=duration":     1,
+	}, counters)
+}
+
+func TestMetricsSanitizerError(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	sanitizer := NewMetricsSanitizer("failing", failingSanitizer{}, metricsFactory)
+	_, err := sanitizer.Sanitize(&zc.Span{})
+	assert.Equal(t, errFailingSanitizer, err)
+
+	counters, _ := metricsFactory.Snapshot()
+	assert.Equal(t, map[string]int64{
+		"sanitizer.invocations|sanitizer=failing": 1,
+		"sanitizer.errors|sanitizer=failing":      1,
+	}, counters)
+}
+
+func TestChainedSanitizerWithMetrics(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	chain := NewChainedSanitizerWithMetrics(metricsFactory,
+		NewSpanDurationSanitizer(zap.NewNop()),
+		NewNoopSanitizer(),
+		NewParentIDSanitizer(zap.NewNop()),
+	)
+	assert.Len(t, chain.ChainedSanitizer, 2)
+
+	zero := int64(0)
+	_, err := chain.Sanitize(&zc.Span{ParentID: &zero})
+	require.NoError(t, err)
+
+	counters, _ := metricsFactory.Snapshot()
+	assert.Equal(t, map[string]int64{
+		"sanitizer.invocations|sanitizer=spanDurationSanitizer": 1,
+		"sanitizer.changed|sanitizer=spanDurationSanitizer":     1,
+		"sanitizer.invocations|sanitizer=parentIDSanitizer":     1,
+		"sanitizer.changed|sanitizer=parentIDSanitizer":         1,
+	}, counters)
+}
+
+func TestMetricsSanitizerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling := &cancellingSanitizer{cancel: cancel}
+	chain := NewChainedSanitizerWithMetrics(metrics.NullFactory, cancelling, NewSpanDurationSanitizer(zap.NewNop()))
+
+	_, err := chain.SanitizeCtx(ctx, &zc.Span{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, cancelling.calls)
+}
+
+// timerCountingFactory counts the calls to Record of its timers by name
+type timerCountingFactory struct {
+	metrics.Factory
+	records map[string]int
+}
+
+type countingTimer struct {
+	name    string
+	records map[string]int
+}
+
+func (t countingTimer) Record(time.Duration) {
+	t.records[t.name]++
+}
+
+func (f *timerCountingFactory) Timer(name string, tags map[string]string) metrics.Timer {
+	return countingTimer{name: name + "|" + tags["sanitizer"], records: f.records}
+}
+
+func TestChainedSanitizerWithMetricsLatency(t *testing.T) {
+	factory := &timerCountingFactory{Factory: metrics.NullFactory, records: make(map[string]int)}
+	chain := NewChainedSanitizerWithMetrics(factory,
+		NewSpanDurationSanitizer(zap.NewNop()),
+		failingSanitizer{},
+		NewParentIDSanitizer(zap.NewNop()),
+	)
+	_, err := chain.Sanitize(&zc.Span{})
+	assert.Equal(t, errFailingSanitizer, err)
+	_, err = chain.Sanitize(&zc.Span{})
+	assert.Equal(t, errFailingSanitizer, err)
+
+	assert.Equal(t, map[string]int{
+		"sanitizer.chain.latency|":                2,
+		"sanitizer.latency|spanDurationSanitizer": 2,
+		"sanitizer.latency|failingSanitizer":      2,
+	}, factory.records)
+}
+
+func TestInstrumentedSanitizerLatency(t *testing.T) {
+	factory := &timerCountingFactory{Factory: metrics.NullFactory, records: make(map[string]int)}
+	chain := NewInstrumentedSanitizer(factory,
+		NewSpanDurationSanitizer(zap.NewNop()),
+		NewParentIDSanitizer(zap.NewNop()),
+	)
+	assert.IsType(t, &spanDurationSanitizer{}, chain.ChainedSanitizer[0])
+	_, err := chain.Sanitize(&zc.Span{})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"sanitizer.chain.latency|": 1}, factory.records)
+}
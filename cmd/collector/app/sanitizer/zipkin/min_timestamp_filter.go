@@ -0,0 +1,120 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"sync"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewMinTimestampFilter returns a batch sanitizer that drops spans whose timestamp precedes cutoffMicros,
+// e.g. data predating a migration. Spans without a timestamp, or with a zero timestamp, are dropped
+// if dropMissing is set and kept otherwise. Dropped spans are counted per service, for up to
+// maxServiceCounters services.
+func NewMinTimestampFilter(cutoffMicros int64, dropMissing bool, logger *zap.Logger, metricsFactory metrics.Factory) BatchSanitizer {
+	return &minTimestampFilter{
+		cutoff:      cutoffMicros,
+		dropMissing: dropMissing,
+		log:         spanLogger{logger},
+		dropped:     newServiceCounters(metricsFactory, "spans.dropped", map[string]string{"reason": "before-cutoff"}),
+	}
+}
+
+type minTimestampFilter struct {
+	cutoff      int64
+	dropMissing bool
+	log         spanLogger
+	dropped     *serviceCounters
+}
+
+func (f *minTimestampFilter) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	return filterSpans(spans, f.keep)
+}
+
+func (f *minTimestampFilter) keep(span *zc.Span) bool {
+	if span.Timestamp == nil || *span.Timestamp == 0 {
+		if !f.dropMissing {
+			return true
+		}
+	} else if *span.Timestamp >= f.cutoff {
+		return true
+	}
+	service := findServiceName(span)
+	f.log.ForSpan(span).Debug("Dropping span older than the cutoff", zap.String("service", service))
+	f.dropped.forService(service).Inc(1)
+	return false
+}
+
+const (
+	// maxServiceCounters is the number of distinct services serviceCounters creates counters for. Service
+	// names come from clients, so they are capped to bound the number of metrics.
+	maxServiceCounters = 200
+	// otherServices is the service label of the counter shared by the services beyond maxServiceCounters
+	otherServices = "other-services"
+)
+
+// serviceCounters lazily creates counters labeled with the service name.
+type serviceCounters struct {
+	sync.Mutex
+	factory     metrics.Factory
+	name        string
+	tags        map[string]string
+	counters    map[string]metrics.Counter
+	maxServices int
+}
+
+func newServiceCounters(factory metrics.Factory, name string, tags map[string]string) *serviceCounters {
+	return &serviceCounters{
+		factory:     factory,
+		name:        name,
+		tags:        tags,
+		counters:    make(map[string]metrics.Counter),
+		maxServices: maxServiceCounters,
+	}
+}
+
+func (c *serviceCounters) forService(service string) metrics.Counter {
+	if service == "" {
+		service = "unknown"
+	}
+	c.Lock()
+	defer c.Unlock()
+	if counter, ok := c.counters[service]; ok {
+		return counter
+	}
+	if len(c.counters) >= c.maxServices {
+		service = otherServices
+		if counter, ok := c.counters[service]; ok {
+			return counter
+		}
+	}
+	tags := map[string]string{"service": service}
+	for k, v := range c.tags {
+		tags[k] = v
+	}
+	counter := c.factory.Counter(c.name, tags)
+	c.counters[service] = counter
+	return counter
+}
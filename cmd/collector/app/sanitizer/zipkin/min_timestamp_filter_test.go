@@ -0,0 +1,97 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func timestampedSpan(service string, timestamp *int64) *zc.Span {
+	return &zc.Span{
+		Timestamp:   timestamp,
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV, Host: &zc.Endpoint{ServiceName: service}}},
+	}
+}
+
+func TestMinTimestampFilter(t *testing.T) {
+	var (
+		before = int64(999)
+		at     = int64(1000)
+		zero   = int64(0)
+	)
+	tests := []struct {
+		dropMissing bool
+		expected    []int
+		dropped     map[string]int64
+	}{
+		{
+			dropMissing: false,
+			expected:    []int{1, 2, 3},
+			dropped:     map[string]int64{"spans.dropped|reason=before-cutoff|service=frontend": 1},
+		},
+		{
+			dropMissing: true,
+			expected:    []int{1},
+			dropped: map[string]int64{
+				"spans.dropped|reason=before-cutoff|service=frontend": 1,
+				"spans.dropped|reason=before-cutoff|service=backend":  2,
+			},
+		},
+	}
+	for _, test := range tests {
+		metricsFactory := metrics.NewLocalFactory(0)
+		filter := NewMinTimestampFilter(1000, test.dropMissing, zap.NewNop(), metricsFactory)
+		spans := []*zc.Span{
+			timestampedSpan("frontend", &before),
+			timestampedSpan("frontend", &at),
+			timestampedSpan("backend", &zero),
+			timestampedSpan("backend", nil),
+		}
+		var expected []*zc.Span
+		for _, i := range test.expected {
+			expected = append(expected, spans[i])
+		}
+		assert.Equal(t, expected, filter.SanitizeBatch(spans))
+		counters, _ := metricsFactory.Snapshot()
+		assert.Equal(t, test.dropped, counters)
+	}
+}
+
+func TestServiceCountersLimit(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	counters := newServiceCounters(metricsFactory, "spans.dropped", nil)
+	counters.maxServices = 2
+	for _, service := range []string{"frontend", "backend", "mysql", "redis", "frontend"} {
+		counters.forService(service).Inc(1)
+	}
+	snapshot, _ := metricsFactory.Snapshot()
+	assert.Equal(t, map[string]int64{
+		"spans.dropped|service=frontend":       2,
+		"spans.dropped|service=backend":        1,
+		"spans.dropped|service=other-services": 2,
+	}, snapshot)
+}
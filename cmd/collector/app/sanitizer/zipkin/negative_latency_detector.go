@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const negativeLatencyTag = "warnNegativeLatency"
+
+// coreAnnotationPairs lists the start and end annotations of the client and server sides of a span.
+var coreAnnotationPairs = [][2]string{
+	{zc.CLIENT_SEND, zc.CLIENT_RECV},
+	{zc.SERVER_RECV, zc.SERVER_SEND},
+}
+
+// NewNegativeLatencyDetector returns a sanitizer that flags spans where the end annotation of a core
+// annotation pair, 'cr' or 'ss', precedes its start annotation, 'cs' or 'sr'. Each inverted pair and its
+// negative latency in microseconds is recorded in a 'warnNegativeLatency' tag, e.g. "cs/cr=-50".
+// Pairs with a missing annotation are skipped, and annotations are never modified.
+func NewNegativeLatencyDetector(logger *zap.Logger) Sanitizer {
+	return &negativeLatencyDetector{log: spanLogger{logger}}
+}
+
+type negativeLatencyDetector struct {
+	log spanLogger
+}
+
+func (s *negativeLatencyDetector) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var inverted []string
+	for _, pair := range coreAnnotationPairs {
+		var start, end *zc.Annotation
+		for _, anno := range span.Annotations {
+			switch anno.Value {
+			case pair[0]:
+				start = anno
+			case pair[1]:
+				end = anno
+			}
+		}
+		if start == nil || end == nil || end.Timestamp >= start.Timestamp {
+			continue
+		}
+		latency := end.Timestamp - start.Timestamp
+		s.log.ForSpan(span).Debug("Negative latency between annotations",
+			zap.String("start", pair[0]),
+			zap.String("end", pair[1]),
+			zap.Int64("latency", latency))
+		inverted = append(inverted, pair[0]+"/"+pair[1]+"="+strconv.FormatInt(latency, 10))
+	}
+	if len(inverted) > 0 {
+		appendStringTag(span, negativeLatencyTag, strings.Join(inverted, ","))
+	}
+	return span, nil
+}
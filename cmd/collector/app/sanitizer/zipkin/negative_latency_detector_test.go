@@ -0,0 +1,56 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestNegativeLatencyDetector(t *testing.T) {
+	detector := NewNegativeLatencyDetector(zap.NewNop())
+
+	span, err := detector.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Timestamp: 150},
+			{Value: zc.SERVER_RECV, Timestamp: 110},
+			{Value: zc.SERVER_SEND, Timestamp: 120},
+			{Value: zc.CLIENT_RECV, Timestamp: 100},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{150, 110, 120, 100}, annotationTimestamps(span))
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(negativeLatencyTag, "cs/cr=-50")}, span.BinaryAnnotations)
+
+	span, err = detector.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.SERVER_SEND, Timestamp: 100},
+			{Value: zc.CLIENT_SEND, Timestamp: 150},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, span.BinaryAnnotations)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"go.uber.org/zap"
+
+	zConv "github.com/uber/jaeger/model/converter/thrift/zipkin"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const numericDecodeFailedTag = "errNumericDecodeFailed"
+
+// NewNumericDecodeGuardSanitizer returns a sanitizer that makes sure the values of all I16, I32, I64 and DOUBLE
+// binary annotations can be decoded, so that downstream consumers never fail on them. Values are decoded as
+// when converting spans to the domain model, which accepts values longer than their type. Annotations that cannot
+// be decoded are converted to STRING annotations holding the base64-encoded original value, and their keys are
+// recorded in an 'errNumericDecodeFailed' tag.
+func NewNumericDecodeGuardSanitizer(logger *zap.Logger) Sanitizer {
+	return &numericDecodeGuardSanitizer{log: spanLogger{logger}}
+}
+
+type numericDecodeGuardSanitizer struct {
+	log spanLogger
+}
+
+func (s *numericDecodeGuardSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var failed []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if err := decodeNumeric(binAnno); err != nil {
+			s.log.ForSpan(span).Warn("Cannot decode numeric annotation", zap.String("key", binAnno.Key), zap.Error(err))
+			binAnno.Value = []byte(base64.StdEncoding.EncodeToString(binAnno.Value))
+			binAnno.AnnotationType = zc.AnnotationType_STRING
+			failed = append(failed, binAnno.Key)
+		}
+	}
+	if len(failed) > 0 {
+		appendStringTag(span, numericDecodeFailedTag, strings.Join(failed, ","))
+	}
+	return span, nil
+}
+
+// decodeNumeric decodes the value of numeric binary annotations with the domain model converter.
+func decodeNumeric(binAnno *zc.BinaryAnnotation) error {
+	switch binAnno.AnnotationType {
+	case zc.AnnotationType_I16, zc.AnnotationType_I32, zc.AnnotationType_I64, zc.AnnotationType_DOUBLE:
+		_, err := zConv.ToDomainTag(binAnno)
+		return err
+	}
+	return nil
+}
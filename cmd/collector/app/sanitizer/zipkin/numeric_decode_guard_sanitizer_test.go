@@ -0,0 +1,56 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestNumericDecodeGuardSanitizer(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	sanitizer := NewNumericDecodeGuardSanitizer(logger)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: "valid", Value: []byte{0, 1}, AnnotationType: zc.AnnotationType_I16},
+			{Key: "long", Value: []byte{0, 0, 0, 1, 2}, AnnotationType: zc.AnnotationType_I32},
+			{Key: "short", Value: []byte{1, 2, 3}, AnnotationType: zc.AnnotationType_I64},
+			{Key: "ratio", Value: nil, AnnotationType: zc.AnnotationType_DOUBLE},
+			stringTag("text", "abc"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		{Key: "valid", Value: []byte{0, 1}, AnnotationType: zc.AnnotationType_I16},
+		{Key: "long", Value: []byte{0, 0, 0, 1, 2}, AnnotationType: zc.AnnotationType_I32},
+		stringTag("short", "AQID"),
+		stringTag("ratio", ""),
+		stringTag("text", "abc"),
+		stringTag(numericDecodeFailedTag, "short,ratio"),
+	}, span.BinaryAnnotations)
+	assert.Len(t, log.Lines(), 2)
+	assert.Equal(t, "short", log.JSONLine(0)["key"])
+}
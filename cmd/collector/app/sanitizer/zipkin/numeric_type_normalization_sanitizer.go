@@ -0,0 +1,87 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"math"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewNumericTypeNormalizationSanitizer returns a sanitizer that re-encodes the I16, I32 and I64 binary
+// annotations with the given keys, e.g. 'rpc.status', as targetType, which must be I16, I32 or I64, so that
+// equal values are stored alike. Values that do not fit in targetType, or have an invalid length, are left
+// unchanged. The sanitizer does nothing for other target types.
+func NewNumericTypeNormalizationSanitizer(keys []string, targetType zc.AnnotationType) Sanitizer {
+	var encode func(value int64) ([]byte, bool)
+	switch targetType {
+	case zc.AnnotationType_I16:
+		encode = func(value int64) ([]byte, bool) {
+			return int16Bytes(int16(value)), value >= math.MinInt16 && value <= math.MaxInt16
+		}
+	case zc.AnnotationType_I32:
+		encode = func(value int64) ([]byte, bool) {
+			return int32Bytes(int32(value)), value >= math.MinInt32 && value <= math.MaxInt32
+		}
+	case zc.AnnotationType_I64:
+		encode = func(value int64) ([]byte, bool) {
+			return int64Bytes(value), true
+		}
+	default:
+		return NewNoopSanitizer()
+	}
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[key] = struct{}{}
+	}
+	return &numericTypeNormalizationSanitizer{keys: keySet, targetType: targetType, encode: encode}
+}
+
+type numericTypeNormalizationSanitizer struct {
+	keys       map[string]struct{}
+	targetType zc.AnnotationType
+	encode     func(value int64) ([]byte, bool)
+}
+
+func (s *numericTypeNormalizationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.AnnotationType == s.targetType {
+			continue
+		}
+		if _, ok := s.keys[binAnno.Key]; !ok {
+			continue
+		}
+		switch binAnno.AnnotationType {
+		case zc.AnnotationType_I16, zc.AnnotationType_I32, zc.AnnotationType_I64:
+		default:
+			continue
+		}
+		value, err := decodeInt(binAnno)
+		if err != nil {
+			continue
+		}
+		if encoded, ok := s.encode(value); ok {
+			binAnno.AnnotationType = s.targetType
+			binAnno.Value = encoded
+		}
+	}
+	return span, nil
+}
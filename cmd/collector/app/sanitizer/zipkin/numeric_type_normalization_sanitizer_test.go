@@ -0,0 +1,86 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func intTag(key string, annoType zc.AnnotationType, value int64) *zc.BinaryAnnotation {
+	binAnno := &zc.BinaryAnnotation{Key: key, AnnotationType: annoType}
+	switch annoType {
+	case zc.AnnotationType_I16:
+		binAnno.Value = int16Bytes(int16(value))
+	case zc.AnnotationType_I32:
+		binAnno.Value = int32Bytes(int32(value))
+	default:
+		binAnno.Value = int64Bytes(value)
+	}
+	return binAnno
+}
+
+func TestNumericTypeNormalizationSanitizer(t *testing.T) {
+	types := []zc.AnnotationType{zc.AnnotationType_I16, zc.AnnotationType_I32, zc.AnnotationType_I64}
+	for _, target := range types {
+		sanitizer := NewNumericTypeNormalizationSanitizer([]string{"rpc.status"}, target)
+		for _, source := range types {
+			for _, value := range []int64{0, 14, -2, 32767, -32768} {
+				span, err := sanitizer.Sanitize(&zc.Span{
+					BinaryAnnotations: []*zc.BinaryAnnotation{intTag("rpc.status", source, value)},
+				})
+				require.NoError(t, err)
+				binAnno := span.BinaryAnnotations[0]
+				assert.Equal(t, intTag("rpc.status", target, value), binAnno, "%v to %v: %d", source, target, value)
+				actual, err := decodeInt(binAnno)
+				require.NoError(t, err)
+				assert.Equal(t, value, actual)
+			}
+		}
+	}
+}
+
+func TestNumericTypeNormalizationSanitizerUnchanged(t *testing.T) {
+	sanitizer := NewNumericTypeNormalizationSanitizer([]string{"rpc.status"}, zc.AnnotationType_I16)
+	binAnnos := []*zc.BinaryAnnotation{
+		intTag("rpc.status", zc.AnnotationType_I32, 40000),
+		intTag("rpc.status", zc.AnnotationType_I64, -1<<40),
+		{Key: "rpc.status", AnnotationType: zc.AnnotationType_I32, Value: []byte{1, 2}},
+		stringTag("rpc.status", "14"),
+		intTag("other", zc.AnnotationType_I64, 14),
+	}
+	expected := []*zc.BinaryAnnotation{
+		intTag("rpc.status", zc.AnnotationType_I32, 40000),
+		intTag("rpc.status", zc.AnnotationType_I64, -1<<40),
+		{Key: "rpc.status", AnnotationType: zc.AnnotationType_I32, Value: []byte{1, 2}},
+		stringTag("rpc.status", "14"),
+		intTag("other", zc.AnnotationType_I64, 14),
+	}
+	span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: binAnnos})
+	require.NoError(t, err)
+	assert.Equal(t, expected, span.BinaryAnnotations)
+
+	assert.Equal(t, NewNoopSanitizer(), NewNumericTypeNormalizationSanitizer([]string{"rpc.status"}, zc.AnnotationType_DOUBLE))
+}
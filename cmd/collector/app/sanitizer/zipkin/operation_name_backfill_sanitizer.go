@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const operationNameBackfilledTag = "warnOperationNameBackfilled"
+
+// NewOperationNameBackfillSanitizer returns a sanitizer that derives a name for spans with an empty name
+// from their tags: 'http.method' followed by 'http.route' if present, e.g. "GET /users/{id}", or else
+// 'db.system' followed by 'db.operation' if present, e.g. "mysql SELECT". Local spans, i.e. those with
+// an 'lc' tag, keep their empty name. Backfilled spans are tagged with 'warnOperationNameBackfilled'.
+func NewOperationNameBackfillSanitizer() Sanitizer {
+	return &operationNameBackfillSanitizer{}
+}
+
+type operationNameBackfillSanitizer struct {
+}
+
+func (s *operationNameBackfillSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Name != "" || findBinaryAnnotation(span, zc.LOCAL_COMPONENT) != nil {
+		return span, nil
+	}
+	name := joinTagValues(span, "http.method", "http.route")
+	if name == "" {
+		name = joinTagValues(span, "db.system", "db.operation")
+	}
+	if name == "" {
+		return span, nil
+	}
+	span.Name = name
+	appendStringTag(span, operationNameBackfilledTag, name)
+	return span, nil
+}
+
+// joinTagValues returns the value of the first string tag, followed by the value of the second one if present,
+// separated by a space. An empty string is returned if the first tag is missing or empty.
+func joinTagValues(span *zc.Span, firstKey, secondKey string) string {
+	first := findBinaryAnnotation(span, firstKey)
+	if first == nil || first.AnnotationType != zc.AnnotationType_STRING || len(first.Value) == 0 {
+		return ""
+	}
+	second := findBinaryAnnotation(span, secondKey)
+	if second == nil || second.AnnotationType != zc.AnnotationType_STRING || len(second.Value) == 0 {
+		return string(first.Value)
+	}
+	return string(first.Value) + " " + string(second.Value)
+}
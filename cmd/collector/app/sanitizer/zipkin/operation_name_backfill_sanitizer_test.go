@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestOperationNameBackfillSanitizer(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []*zc.BinaryAnnotation
+		expected string
+	}{
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag("http.method", "GET"), stringTag("http.route", "/users/{id}")},
+			expected: "GET /users/{id}",
+		},
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag("http.method", "POST")},
+			expected: "POST",
+		},
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag("db.operation", "SELECT"), stringTag("db.system", "mysql")},
+			expected: "mysql SELECT",
+		},
+		{
+			name:     "get-user",
+			tags:     []*zc.BinaryAnnotation{stringTag("http.method", "GET")},
+			expected: "get-user",
+		},
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag(zc.LOCAL_COMPONENT, "cache"), stringTag("db.system", "redis")},
+			expected: "",
+		},
+		{
+			tags:     []*zc.BinaryAnnotation{stringTag("component", "grpc")},
+			expected: "",
+		},
+	}
+	sanitizer := NewOperationNameBackfillSanitizer()
+	for _, test := range tests {
+		tagCount := len(test.tags)
+		span, err := sanitizer.Sanitize(&zc.Span{Name: test.name, BinaryAnnotations: test.tags})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.Name)
+		if test.name == "" && test.expected != "" {
+			assert.Equal(t, stringTag(operationNameBackfilledTag, test.expected), span.BinaryAnnotations[tagCount])
+		} else {
+			assert.Len(t, span.BinaryAnnotations, tagCount)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	blocklistedOperationNameTag = "warnBlocklistedOperationName"
+	operationNamePlaceholder    = "{id}"
+)
+
+// NewOperationNameBlocklistSanitizer returns a sanitizer that guards against high-cardinality span names,
+// e.g. names containing a long run of digits or a UUID, by matching them against the blocklisted patterns.
+// If templatize is set, the matching parts of the name are replaced with an '{id}' placeholder, otherwise
+// the name is left as is. In both cases the original name is recorded in a 'warnBlocklistedOperationName' tag.
+func NewOperationNameBlocklistSanitizer(patterns []*regexp.Regexp, logger *zap.Logger, templatize bool) Sanitizer {
+	return &operationNameBlocklistSanitizer{
+		patterns:   patterns,
+		log:        spanLogger{logger},
+		templatize: templatize,
+	}
+}
+
+type operationNameBlocklistSanitizer struct {
+	patterns   []*regexp.Regexp
+	log        spanLogger
+	templatize bool
+}
+
+func (s *operationNameBlocklistSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	name := span.Name
+	matched := false
+	for _, pattern := range s.patterns {
+		if !pattern.MatchString(name) {
+			continue
+		}
+		matched = true
+		if s.templatize {
+			name = pattern.ReplaceAllLiteralString(name, operationNamePlaceholder)
+		}
+	}
+	if !matched {
+		return span, nil
+	}
+	s.log.ForSpan(span).Debug("Blocklisted operation name", zap.String("name", span.Name))
+	appendStringTag(span, blocklistedOperationNameTag, span.Name)
+	span.Name = name
+	return span, nil
+}
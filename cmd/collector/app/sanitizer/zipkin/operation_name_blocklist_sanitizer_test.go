@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+var operationNameBlocklist = []*regexp.Regexp{
+	regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`),
+	regexp.MustCompile(`[0-9]{6,}`),
+}
+
+func TestOperationNameBlocklistSanitizer(t *testing.T) {
+	tests := []struct {
+		templatize bool
+		name       string
+		expected   string
+		tagged     bool
+	}{
+		{templatize: true, name: "GET /orders/123456789", expected: "GET /orders/{id}", tagged: true},
+		{
+			templatize: true,
+			name:       "GET /users/1b4e28ba-2fa1-11d2-883f-0016d3cca427/orders/1234567",
+			expected:   "GET /users/{id}/orders/{id}",
+			tagged:     true,
+		},
+		{templatize: false, name: "GET /orders/123456789", expected: "GET /orders/123456789", tagged: true},
+		{templatize: true, name: "GET /orders/{id}", expected: "GET /orders/{id}"},
+		{templatize: true, name: "GET /v2/orders/42", expected: "GET /v2/orders/42"},
+	}
+	for _, test := range tests {
+		sanitizer := NewOperationNameBlocklistSanitizer(operationNameBlocklist, zap.NewNop(), test.templatize)
+		span, err := sanitizer.Sanitize(&zc.Span{Name: test.name})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.Name)
+		if test.tagged {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(blocklistedOperationNameTag, test.name)}, span.BinaryAnnotations)
+		} else {
+			assert.Empty(t, span.BinaryAnnotations)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	sanitizerDuration          = "collector.sanitizer.duration"
+	sanitizerSelfReference     = "collector.sanitizer.self-reference"
+	sanitizerParentID          = "collector.sanitizer.parent-id"
+	sanitizerErrorTag          = "collector.sanitizer.error-tag"
+	sanitizerMaxDuration       = "collector.sanitizer.max-duration"
+	sanitizerMaxTagCount       = "collector.sanitizer.max-tag-count"
+	sanitizerMaxTagValueLength = "collector.sanitizer.max-tag-value-length"
+	sanitizerLogsPerSecond     = "collector.sanitizer.logs-per-second"
+	sanitizerStageMetrics      = "collector.sanitizer.stage-metrics"
+)
+
+// Options holds configuration for the chain of Zipkin span sanitizers
+type Options struct {
+	// EnableDuration enables the span duration sanitizer
+	EnableDuration bool
+	// EnableSelfReference enables the sanitizer of spans that are their own parent
+	EnableSelfReference bool
+	// EnableParentID enables the zero parentID sanitizer
+	EnableParentID bool
+	// EnableErrorTag enables the error tag sanitizer
+	EnableErrorTag bool
+	// MaxDuration is the duration spans are clamped to, 0 disables clamping
+	MaxDuration time.Duration
+	// MaxTagCount is the maximum number of tags of a span, 0 disables the limit
+	MaxTagCount int
+	// MaxTagValueLength is the maximum length in bytes of a tag value, 0 disables the limit
+	MaxTagValueLength int
+	// LogsPerSecond is the number of times per second each sanitizer log message can be written, 0 disables the limit
+	LogsPerSecond int
+	// EnableStageMetrics enables the metrics of each sanitizer, in addition to the latency of the chain
+	EnableStageMetrics bool
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flags *flag.FlagSet) {
+	defaults := DefaultOptions()
+	flags.Bool(sanitizerDuration, defaults.EnableDuration, "Fix missing and negative span durations")
+	flags.Bool(sanitizerSelfReference, defaults.EnableSelfReference, "Turn spans that are their own parent into root spans")
+	flags.Bool(sanitizerParentID, defaults.EnableParentID, "Turn spans with a zero parent ID into root spans")
+	flags.Bool(sanitizerErrorTag, defaults.EnableErrorTag, "Convert string error tags to booleans")
+	flags.Duration(sanitizerMaxDuration, defaults.MaxDuration, "The duration longer spans are clamped to, 0 to disable")
+	flags.Int(sanitizerMaxTagCount, defaults.MaxTagCount, "The maximum number of tags of a span, 0 to disable")
+	flags.Int(sanitizerMaxTagValueLength, defaults.MaxTagValueLength, "The maximum length in bytes of a tag value, 0 to disable")
+	flags.Int(sanitizerLogsPerSecond, defaults.LogsPerSecond, "The number of times per second each sanitizer log message can be written, 0 to disable")
+	flags.Bool(sanitizerStageMetrics, defaults.EnableStageMetrics, "Report invocation, change, error and latency metrics for each sanitizer")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.EnableDuration = v.GetBool(sanitizerDuration)
+	opts.EnableSelfReference = v.GetBool(sanitizerSelfReference)
+	opts.EnableParentID = v.GetBool(sanitizerParentID)
+	opts.EnableErrorTag = v.GetBool(sanitizerErrorTag)
+	opts.MaxDuration = v.GetDuration(sanitizerMaxDuration)
+	opts.MaxTagCount = v.GetInt(sanitizerMaxTagCount)
+	opts.MaxTagValueLength = v.GetInt(sanitizerMaxTagValueLength)
+	opts.LogsPerSecond = v.GetInt(sanitizerLogsPerSecond)
+	opts.EnableStageMetrics = v.GetBool(sanitizerStageMetrics)
+	return opts
+}
+
+// DefaultOptions returns the default values of the flags added by AddFlags, which enable the span duration,
+// zero parent ID and error tag sanitizers the collector has always run. The self reference sanitizer is opt-in.
+func DefaultOptions() Options {
+	return Options{
+		EnableDuration: true,
+		EnableParentID: true,
+		EnableErrorTag: true,
+	}
+}
+
+// NewDefaultSanitizer creates the recommended chain of sanitizers, without metrics: span duration,
+// zero parent ID and error tag.
+func NewDefaultSanitizer(logger *zap.Logger) ChainedSanitizer {
+	return NewChainedSanitizer(
+		NewSpanDurationSanitizer(logger),
+		NewParentIDSanitizer(logger),
+		NewErrorTagSanitizer(),
+	)
+}
+
+// NewSanitizerChainFromOptions creates a chained sanitizer with the stages enabled in opts, timed as by
+// NewInstrumentedSanitizer, or instrumented as by NewChainedSanitizerWithMetrics if opts.EnableStageMetrics
+// is set. Disabled stages are left out of the chain.
+func NewSanitizerChainFromOptions(opts Options, logger *zap.Logger, factory metrics.Factory) *InstrumentedSanitizer {
+	if opts.EnableStageMetrics {
+		return NewChainedSanitizerWithMetrics(factory, newSanitizers(opts, logger)...)
+	}
+	return NewInstrumentedSanitizer(factory, newSanitizers(opts, logger)...)
+}
+
+// newSanitizers returns the stages enabled in opts, in the order they are chained.
+func newSanitizers(opts Options, logger *zap.Logger) []Sanitizer {
+	if opts.LogsPerSecond > 0 {
+		logger = NewRateLimitedLogger(logger, opts.LogsPerSecond)
+	}
+	var sanitizers []Sanitizer
+	if opts.EnableDuration {
+		sanitizers = append(sanitizers, NewSpanDurationSanitizer(logger))
+	}
+	if opts.MaxDuration > 0 {
+		sanitizers = append(sanitizers, NewMaxDurationSanitizer(logger, int64(opts.MaxDuration/time.Microsecond)))
+	}
+	if opts.EnableSelfReference {
+		sanitizers = append(sanitizers, NewSelfReferenceSanitizer(logger))
+	}
+	if opts.EnableParentID {
+		sanitizers = append(sanitizers, NewParentIDSanitizer(logger))
+	}
+	if opts.EnableErrorTag {
+		sanitizers = append(sanitizers, NewErrorTagSanitizer())
+	}
+	if opts.MaxTagValueLength > 0 {
+		sanitizers = append(sanitizers, NewMaxTagValueLengthSanitizer(logger, opts.MaxTagValueLength))
+	}
+	if opts.MaxTagCount > 0 {
+		sanitizers = append(sanitizers, NewMaxTagCountSanitizer(logger, opts.MaxTagCount))
+	}
+	return sanitizers
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/uber/jaeger/pkg/config"
+)
+
+func TestOptionsFromFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.sanitizer.error-tag=false",
+		"--collector.sanitizer.max-duration=1h",
+		"--collector.sanitizer.max-tag-count=100",
+		"--collector.sanitizer.logs-per-second=5",
+		"--collector.sanitizer.stage-metrics",
+	})
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, Options{
+		EnableDuration:     true,
+		EnableParentID:     true,
+		MaxDuration:        time.Hour,
+		MaxTagCount:        100,
+		LogsPerSecond:      5,
+		EnableStageMetrics: true,
+	}, *opts)
+}
+
+func TestNewSanitizerChainFromOptions(t *testing.T) {
+	tests := []struct {
+		flags    []string
+		expected []string
+	}{
+		{
+			expected: []string{"spanDurationSanitizer", "parentIDSanitizer", "errorTagSanitizer"},
+		},
+		{
+			flags:    []string{"--collector.sanitizer.duration=false"},
+			expected: []string{"parentIDSanitizer", "errorTagSanitizer"},
+		},
+		{
+			flags: []string{
+				"--collector.sanitizer.self-reference",
+				"--collector.sanitizer.parent-id=false",
+				"--collector.sanitizer.max-duration=1m",
+				"--collector.sanitizer.max-tag-count=100",
+				"--collector.sanitizer.max-tag-value-length=1024",
+			},
+			expected: []string{
+				"spanDurationSanitizer",
+				"maxDurationSanitizer",
+				"selfReferenceSanitizer",
+				"errorTagSanitizer",
+				"maxTagValueLengthSanitizer",
+				"maxTagCountSanitizer",
+			},
+		},
+		{
+			flags: []string{
+				"--collector.sanitizer.duration=false",
+				"--collector.sanitizer.self-reference=false",
+				"--collector.sanitizer.parent-id=false",
+				"--collector.sanitizer.error-tag=false",
+			},
+		},
+	}
+	for _, test := range tests {
+		v, command := config.Viperize(AddFlags)
+		require.NoError(t, command.ParseFlags(test.flags))
+		chain := NewSanitizerChainFromOptions(*new(Options).InitFromViper(v), zap.NewNop(), metrics.NullFactory)
+		var names []string
+		for _, s := range chain.ChainedSanitizer {
+			names = append(names, sanitizerName(s))
+		}
+		assert.Equal(t, test.expected, names, "%v", test.flags)
+	}
+}
+
+func TestNewDefaultSanitizer(t *testing.T) {
+	var names []string
+	for _, s := range NewDefaultSanitizer(zap.NewNop()) {
+		names = append(names, sanitizerName(s))
+	}
+	assert.Equal(t, []string{"spanDurationSanitizer", "parentIDSanitizer", "errorTagSanitizer"}, names)
+
+	v, _ := config.Viperize(AddFlags)
+	assert.Equal(t, DefaultOptions(), *new(Options).InitFromViper(v))
+}
+
+func TestNewSanitizerChainFromOptionsStageMetrics(t *testing.T) {
+	opts := DefaultOptions()
+	chain := NewSanitizerChainFromOptions(opts, zap.NewNop(), metrics.NullFactory)
+	assert.IsType(t, &spanDurationSanitizer{}, chain.ChainedSanitizer[0])
+
+	opts.EnableStageMetrics = true
+	chain = NewSanitizerChainFromOptions(opts, zap.NewNop(), metrics.NullFactory)
+	require.IsType(t, &metricsSanitizer{}, chain.ChainedSanitizer[0])
+	assert.IsType(t, &spanDurationSanitizer{}, chain.ChainedSanitizer[0].(*metricsSanitizer).inner)
+}
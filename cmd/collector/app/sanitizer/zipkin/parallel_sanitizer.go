@@ -0,0 +1,89 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"runtime"
+	"sync"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewParallelSanitizer returns a BatchSanitizer that applies the sanitizer to the spans of a batch
+// concurrently, using up to workers goroutines, and returns the sanitized spans in their original order.
+// Spans for which the sanitizer returns an error are dropped. The sanitizer must be safe for concurrent use,
+// i.e. it must not keep state shared across spans, which holds for the sanitizers of this package that are
+// not BatchSanitizers.
+//
+// Sanitizing concurrently only pays off when spans can be processed on several CPUs, so the number of workers
+// is also capped at GOMAXPROCS, and with a single worker the spans are sanitized on the calling goroutine.
+// The collector does not use this sanitizer: callers that receive large batches opt in explicitly.
+func NewParallelSanitizer(sanitizer Sanitizer, workers int) BatchSanitizer {
+	if workers < 1 {
+		workers = 1
+	}
+	return &parallelSanitizer{sanitizer: sanitizer, workers: workers}
+}
+
+type parallelSanitizer struct {
+	sanitizer Sanitizer
+	workers   int
+}
+
+func (s *parallelSanitizer) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	sanitized := make([]*zc.Span, len(spans))
+	workers := s.workers
+	if procs := runtime.GOMAXPROCS(0); workers > procs {
+		workers = procs
+	}
+	if workers > len(spans) {
+		workers = len(spans)
+	}
+	if workers <= 1 {
+		for i, span := range spans {
+			// dropped spans are left nil
+			if span, err := s.sanitizer.Sanitize(span); err == nil {
+				sanitized[i] = span
+			}
+		}
+		return filterSpans(sanitized, func(span *zc.Span) bool { return span != nil })
+	}
+	indices := make(chan int, len(spans))
+	for i := range spans {
+		indices <- i
+	}
+	close(indices)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				// dropped spans are left nil
+				if span, err := s.sanitizer.Sanitize(spans[i]); err == nil {
+					sanitized[i] = span
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return filterSpans(sanitized, func(span *zc.Span) bool { return span != nil })
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestParallelSanitizer(t *testing.T) {
+	dropOdd := SanitizerFunc(func(span *zc.Span) (*zc.Span, error) {
+		if span.ID%2 == 1 {
+			return span, errFailingSanitizer
+		}
+		return span, nil
+	})
+	sanitizer := NewChainedSanitizer(NewSpanDurationSanitizer(zap.NewNop()), dropOdd)
+	for _, workers := range []int{0, 1, 4, 200} {
+		spans := make([]*zc.Span, 100)
+		for i := range spans {
+			spans[i] = &zc.Span{ID: int64(i)}
+		}
+		actual := NewParallelSanitizer(sanitizer, workers).SanitizeBatch(spans)
+		if assert.Len(t, actual, 50, "workers=%d", workers) {
+			for i, span := range actual {
+				assert.Equal(t, int64(2*i), span.ID)
+				assert.Equal(t, int64(1), *span.Duration)
+			}
+		}
+	}
+	assert.Empty(t, NewParallelSanitizer(sanitizer, 4).SanitizeBatch(nil))
+}
+
+// BenchmarkSerialSanitizer and BenchmarkParallelSanitizer compare sanitizing a 5000 spans batch serially and
+// concurrently. Run them with e.g. -cpu 1,4: the parallel sanitizer can only be faster with several CPUs.
+func BenchmarkSerialSanitizer(b *testing.B) {
+	sanitizer := benchmarkBatchChain()
+	spans := benchmarkBatch(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, span := range spans {
+			sanitizer.Sanitize(span)
+		}
+	}
+}
+
+func BenchmarkParallelSanitizer(b *testing.B) {
+	sanitizer := NewParallelSanitizer(benchmarkBatchChain(), 8)
+	spans := benchmarkBatch(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sanitizer.SanitizeBatch(spans)
+	}
+}
+
+func benchmarkBatchChain() Sanitizer {
+	return NewChainedSanitizer(
+		NewSpanDurationSanitizer(zap.NewNop()),
+		NewSelfReferenceSanitizer(zap.NewNop()),
+		NewParentIDSanitizer(zap.NewNop()),
+		NewErrorTagSanitizer(),
+		NewKeyNormalizationSanitizer(zap.NewNop()),
+	)
+}
+
+func benchmarkBatch(n int) []*zc.Span {
+	spans := make([]*zc.Span, n)
+	for i := range spans {
+		spans[i] = cowSpan(5)
+	}
+	return spans
+}
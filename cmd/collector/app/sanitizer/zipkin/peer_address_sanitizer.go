@@ -0,0 +1,91 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	badPeerAddressTag = "errBadPeerAddress"
+	peerAddressKey    = "peer.address"
+)
+
+// NewPeerAddressSanitizer returns a sanitizer that normalizes the formatting of the 'peer.service' and
+// 'peer.address' string tags so that the same peer is not split across dependency graph nodes.
+// Surrounding whitespace is trimmed from both, and 'peer.address' values such as '1.2.3.4 : 80' or
+// 'host.:80.' are rewritten to 'host:port'. Addresses that do not have a non-empty host and a valid port
+// are left unchanged and recorded in an 'errBadPeerAddress' tag.
+func NewPeerAddressSanitizer() Sanitizer {
+	return &peerAddressSanitizer{}
+}
+
+type peerAddressSanitizer struct {
+}
+
+func (s *peerAddressSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var badAddresses []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.AnnotationType != zc.AnnotationType_STRING {
+			continue
+		}
+		switch binAnno.Key {
+		case peerServiceKey:
+			if service := strings.TrimSpace(string(binAnno.Value)); service != string(binAnno.Value) {
+				binAnno.Value = []byte(service)
+			}
+		case peerAddressKey:
+			address, ok := normalizePeerAddress(string(binAnno.Value))
+			if !ok {
+				badAddresses = append(badAddresses, string(binAnno.Value))
+			} else if address != string(binAnno.Value) {
+				binAnno.Value = []byte(address)
+			}
+		}
+	}
+	if len(badAddresses) > 0 {
+		appendStringTag(span, badPeerAddressTag, strings.Join(badAddresses, ","))
+	}
+	return span, nil
+}
+
+// normalizePeerAddress trims whitespace and trailing dots around the host and port of a 'host:port'
+// address, and returns false if the address does not have a non-empty host and a valid port.
+func normalizePeerAddress(address string) (string, bool) {
+	trim := func(s string) string {
+		return strings.TrimRight(strings.TrimSpace(s), ".")
+	}
+	i := strings.LastIndex(address, ":")
+	if i < 0 {
+		return address, false
+	}
+	host, port := trim(address[:i]), trim(address[i+1:])
+	if host == "" {
+		return address, false
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return address, false
+	}
+	return host + ":" + port, true
+}
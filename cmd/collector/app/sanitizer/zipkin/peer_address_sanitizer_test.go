@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestPeerAddressSanitizer(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+		bad      bool
+	}{
+		{address: "1.2.3.4:80", expected: "1.2.3.4:80"},
+		{address: "[::1]:8080", expected: "[::1]:8080"},
+		{address: "1.2.3.4 : 80", expected: "1.2.3.4:80"},
+		{address: " 1.2.3.4:80 ", expected: "1.2.3.4:80"},
+		{address: "db.example.com.:5432", expected: "db.example.com:5432"},
+		{address: "1.2.3.4:80.", expected: "1.2.3.4:80"},
+		{address: "1.2.3.4", expected: "1.2.3.4", bad: true},
+		{address: " :80", expected: " :80", bad: true},
+		{address: "1.2.3.4:http", expected: "1.2.3.4:http", bad: true},
+		{address: "1.2.3.4:0", expected: "1.2.3.4:0", bad: true},
+		{address: "1.2.3.4:65536", expected: "1.2.3.4:65536", bad: true},
+	}
+	sanitizer := NewPeerAddressSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{
+			BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(peerAddressKey, test.address)},
+		})
+		require.NoError(t, err)
+		expected := []*zc.BinaryAnnotation{stringTag(peerAddressKey, test.expected)}
+		if test.bad {
+			expected = append(expected, stringTag(badPeerAddressTag, test.address))
+		}
+		assert.Equal(t, expected, span.BinaryAnnotations, test.address)
+	}
+}
+
+func TestPeerAddressSanitizerPeerService(t *testing.T) {
+	sanitizer := NewPeerAddressSanitizer()
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag(peerServiceKey, " db "),
+			{Key: peerAddressKey, Value: []byte{1, 2, 3, 4}, AnnotationType: zc.AnnotationType_BYTES},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag(peerServiceKey, "db"),
+		{Key: peerAddressKey, Value: []byte{1, 2, 3, 4}, AnnotationType: zc.AnnotationType_BYTES},
+	}, span.BinaryAnnotations)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	peerIPv4NormalizedTag = "warnPeerIPv4Normalized"
+	badPeerIPv4Tag        = "warnBadPeerIPv4"
+	peerIPv4Key           = "peer.ipv4"
+)
+
+// NewPeerIPv4NormalizeSanitizer returns a sanitizer that rewrites the 'peer.ipv4' tag as a dotted-quad STRING.
+// The tag may hold an integer, either typed or as a decimal string, a dotted-quad, or a '0x' prefixed hex string.
+// If the tag has an endpoint without an IPv4 address, the address is copied to the endpoint.
+// The original value of a rewritten tag is recorded in a 'warnPeerIPv4Normalized' tag, and a value that
+// cannot be parsed in a 'warnBadPeerIPv4' tag.
+func NewPeerIPv4NormalizeSanitizer() Sanitizer {
+	return &peerIPv4NormalizeSanitizer{}
+}
+
+type peerIPv4NormalizeSanitizer struct {
+}
+
+func (s *peerIPv4NormalizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	binAnno := findBinaryAnnotation(span, peerIPv4Key)
+	if binAnno == nil {
+		return span, nil
+	}
+	original, _ := valueString(binAnno)
+	ipv4, ok := parsePeerIPv4(binAnno)
+	if !ok {
+		appendStringTag(span, badPeerIPv4Tag, original)
+		return span, nil
+	}
+	if binAnno.Host != nil && binAnno.Host.Ipv4 == 0 {
+		binAnno.Host.Ipv4 = int32(ipv4)
+	}
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, ipv4)
+	normalized := ip.String()
+	if binAnno.AnnotationType == zc.AnnotationType_STRING && original == normalized {
+		return span, nil
+	}
+	binAnno.AnnotationType = zc.AnnotationType_STRING
+	binAnno.Value = []byte(normalized)
+	appendStringTag(span, peerIPv4NormalizedTag, original)
+	return span, nil
+}
+
+// parsePeerIPv4 parses the value of a 'peer.ipv4' tag into an IPv4 address packed in a uint32.
+func parsePeerIPv4(binAnno *zc.BinaryAnnotation) (uint32, bool) {
+	if binAnno.AnnotationType != zc.AnnotationType_STRING {
+		i, err := decodeInt(binAnno)
+		if err != nil || i < -1<<31 || i > 1<<32-1 {
+			return 0, false
+		}
+		return uint32(i), true
+	}
+	value := strings.TrimSpace(string(binAnno.Value))
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		i, err := strconv.ParseUint(value[2:], 16, 32)
+		return uint32(i), err == nil
+	}
+	if i, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return uint32(i), true
+	}
+	ip := net.ParseIP(value).To4()
+	if ip == nil {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(ip), true
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestPeerIPv4NormalizeSanitizer(t *testing.T) {
+	tests := []struct {
+		tag      *zc.BinaryAnnotation
+		expected []*zc.BinaryAnnotation
+	}{
+		{
+			tag:      stringTag(peerIPv4Key, "192.168.1.1"),
+			expected: []*zc.BinaryAnnotation{stringTag(peerIPv4Key, "192.168.1.1")},
+		},
+		{
+			tag: stringTag(peerIPv4Key, "3232235777"),
+			expected: []*zc.BinaryAnnotation{
+				stringTag(peerIPv4Key, "192.168.1.1"),
+				stringTag(peerIPv4NormalizedTag, "3232235777"),
+			},
+		},
+		{
+			tag: stringTag(peerIPv4Key, "0xC0A80101"),
+			expected: []*zc.BinaryAnnotation{
+				stringTag(peerIPv4Key, "192.168.1.1"),
+				stringTag(peerIPv4NormalizedTag, "0xC0A80101"),
+			},
+		},
+		{
+			tag: &zc.BinaryAnnotation{Key: peerIPv4Key, Value: []byte{0xC0, 0xA8, 0x01, 0x01}, AnnotationType: zc.AnnotationType_I32},
+			expected: []*zc.BinaryAnnotation{
+				stringTag(peerIPv4Key, "192.168.1.1"),
+				stringTag(peerIPv4NormalizedTag, "-1062731519"),
+			},
+		},
+		{
+			tag: stringTag(peerIPv4Key, "192.168.1"),
+			expected: []*zc.BinaryAnnotation{
+				stringTag(peerIPv4Key, "192.168.1"),
+				stringTag(badPeerIPv4Tag, "192.168.1"),
+			},
+		},
+	}
+	sanitizer := NewPeerIPv4NormalizeSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: []*zc.BinaryAnnotation{test.tag}})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations)
+	}
+}
+
+func TestPeerIPv4NormalizeSanitizerEndpoint(t *testing.T) {
+	tag := stringTag(peerIPv4Key, "3232235777")
+	tag.Host = &zc.Endpoint{ServiceName: "redis"}
+	span, err := NewPeerIPv4NormalizeSanitizer().Sanitize(&zc.Span{BinaryAnnotations: []*zc.BinaryAnnotation{tag}})
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.1", string(span.BinaryAnnotations[0].Value))
+	assert.Equal(t, int32(-1062731519), span.BinaryAnnotations[0].Host.Ipv4)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	queueWaitKey           = "queue.wait_us"
+	badQueueAnnotationsTag = "warnBadQueueAnnotations"
+)
+
+// NewQueueTimeSanitizer returns a sanitizer that computes the time a message waited in a queue from
+// the enqueue and dequeue timestamp tags, given in microseconds as STRING or I64 values, and records it
+// in a 'queue.wait_us' I64 tag. If the dequeue timestamp precedes the enqueue timestamp, the span is tagged
+// with 'warnBadQueueAnnotations' instead. Spans without both tags are left untouched.
+func NewQueueTimeSanitizer(enqueueKey, dequeueKey string) Sanitizer {
+	return &queueTimeSanitizer{enqueueKey: enqueueKey, dequeueKey: dequeueKey}
+}
+
+type queueTimeSanitizer struct {
+	enqueueKey string
+	dequeueKey string
+}
+
+func (s *queueTimeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if findBinaryAnnotation(span, queueWaitKey) != nil {
+		return span, nil
+	}
+	enqueue, ok := timestampTag(span, s.enqueueKey)
+	if !ok {
+		return span, nil
+	}
+	dequeue, ok := timestampTag(span, s.dequeueKey)
+	if !ok {
+		return span, nil
+	}
+	if dequeue < enqueue {
+		appendStringTag(span, badQueueAnnotationsTag, strconv.FormatInt(dequeue-enqueue, 10))
+		return span, nil
+	}
+	appendInt64Tag(span, queueWaitKey, dequeue-enqueue)
+	return span, nil
+}
+
+// timestampTag returns the value of the first tag with the given key holding a STRING or I64 integer.
+func timestampTag(span *zc.Span, key string) (int64, bool) {
+	binAnno := findBinaryAnnotation(span, key)
+	if binAnno == nil {
+		return 0, false
+	}
+	switch binAnno.AnnotationType {
+	case zc.AnnotationType_STRING:
+		ts, err := strconv.ParseInt(strings.TrimSpace(string(binAnno.Value)), 10, 64)
+		return ts, err == nil
+	case zc.AnnotationType_I64:
+		ts, err := decodeInt(binAnno)
+		return ts, err == nil
+	}
+	return 0, false
+}
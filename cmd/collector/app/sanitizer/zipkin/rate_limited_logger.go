@@ -0,0 +1,139 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewRateLimitedLogger returns a logger that writes each distinct message at most perSecond times per second,
+// e.g. for sanitizer warnings caused by a flood of malformed spans. The first occurrence of a message in a
+// second is always written. The number of occurrences suppressed in a second is summarized in a single
+// 'Suppressed log messages' entry, written with the next entry after that second, or when the logger is synced.
+func NewRateLimitedLogger(logger *zap.Logger, perSecond int) *zap.Logger {
+	limiter := newLogRateLimiter(perSecond, time.Second)
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &rateLimitedCore{Core: core, limiter: limiter}
+	}))
+}
+
+// rateLimitedCore wraps a zapcore.Core to drop the entries rejected by its limiter. The limiter is shared
+// with the cores derived by With, so the limits apply across the fields added by spanLogger.
+type rateLimitedCore struct {
+	zapcore.Core
+	limiter *logRateLimiter
+}
+
+func (c *rateLimitedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitedCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+func (c *rateLimitedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	allowed, suppressed := c.limiter.allow(ent.Message)
+	c.writeSummary(suppressed)
+	if !allowed {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *rateLimitedCore) Sync() error {
+	c.writeSummary(c.limiter.flush())
+	return c.Core.Sync()
+}
+
+func (c *rateLimitedCore) writeSummary(suppressed map[string]int) {
+	messages := make([]string, 0, len(suppressed))
+	for msg := range suppressed {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	for _, msg := range messages {
+		c.Core.Write(
+			zapcore.Entry{Level: zapcore.WarnLevel, Time: c.limiter.timeNow(), Message: "Suppressed log messages"},
+			[]zapcore.Field{zap.String("message", msg), zap.Int("suppressed", suppressed[msg])},
+		)
+	}
+}
+
+// logRateLimiter counts the occurrences of each message in the current interval.
+type logRateLimiter struct {
+	sync.Mutex
+	limit      int
+	interval   time.Duration
+	timeNow    func() time.Time
+	start      time.Time
+	counts     map[string]int
+	suppressed map[string]int
+}
+
+func newLogRateLimiter(limit int, interval time.Duration) *logRateLimiter {
+	return &logRateLimiter{
+		limit:      limit,
+		interval:   interval,
+		timeNow:    time.Now,
+		counts:     make(map[string]int),
+		suppressed: make(map[string]int),
+	}
+}
+
+// allow returns whether the message can be written, and the suppressed counts of the previous interval
+// if it has just ended.
+func (l *logRateLimiter) allow(msg string) (bool, map[string]int) {
+	l.Lock()
+	defer l.Unlock()
+	var suppressed map[string]int
+	if now := l.timeNow(); now.Sub(l.start) >= l.interval {
+		suppressed = l.reset(now)
+	}
+	l.counts[msg]++
+	if l.counts[msg] > l.limit && l.counts[msg] > 1 {
+		l.suppressed[msg]++
+		return false, suppressed
+	}
+	return true, suppressed
+}
+
+// flush returns the suppressed counts of the current interval, and starts a new one.
+func (l *logRateLimiter) flush() map[string]int {
+	l.Lock()
+	defer l.Unlock()
+	return l.reset(l.timeNow())
+}
+
+func (l *logRateLimiter) reset(now time.Time) map[string]int {
+	suppressed := l.suppressed
+	l.start = now
+	l.counts = make(map[string]int)
+	l.suppressed = make(map[string]int)
+	if len(suppressed) == 0 {
+		return nil
+	}
+	return suppressed
+}
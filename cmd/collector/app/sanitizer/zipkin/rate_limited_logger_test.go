@@ -0,0 +1,75 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestRateLimitedLogger(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	now := time.Unix(1500000000, 0)
+	limiter := newLogRateLimiter(10, time.Second)
+	limiter.timeNow = func() time.Time { return now }
+	logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &rateLimitedCore{Core: core, limiter: limiter}
+	}))
+
+	spanLog := spanLogger{logger}
+	for i := 0; i < 1000; i++ {
+		spanLog.ForSpan(&zc.Span{TraceID: int64(i)}).Warn("Bad span")
+	}
+	logger.Warn("Other warning")
+	assert.Len(t, log.Lines(), 11)
+
+	now = now.Add(time.Second)
+	logger.Warn("Bad span")
+	if assert.Len(t, log.Lines(), 13) {
+		assert.Contains(t, log.Lines()[11], `"msg":"Suppressed log messages","message":"Bad span","suppressed":990`)
+		assert.Equal(t, "Bad span", log.JSONLine(12)["msg"])
+	}
+
+	for i := 0; i < 20; i++ {
+		logger.Warn("Bad span")
+	}
+	logger.Sync()
+	if assert.Len(t, log.Lines(), 23) {
+		assert.Contains(t, log.Lines()[22], `"suppressed":11`)
+	}
+}
+
+func TestRateLimitedLoggerFirstOccurrence(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	logger = NewRateLimitedLogger(logger, 0)
+	logger.Warn("First")
+	logger.Warn("First")
+	logger.Warn("Second")
+	logger.Debug("Third")
+	assert.Len(t, log.Lines(), 3)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"time"
+
+	"github.com/uber/jaeger/model"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const receivedAtTag = "collector.received_at"
+
+// NewReceivedTimestampSanitizer returns a sanitizer that records when the collector processed a span,
+// to help debug clock skew. The current time given by clock is appended in microseconds as an I64
+// 'collector.received_at' tag, unless the span already has one, e.g. because the chain is re-run.
+func NewReceivedTimestampSanitizer(clock func() time.Time) Sanitizer {
+	return &receivedTimestampSanitizer{clock: clock}
+}
+
+type receivedTimestampSanitizer struct {
+	clock func() time.Time
+}
+
+func (s *receivedTimestampSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if findBinaryAnnotation(span, receivedAtTag) != nil {
+		return span, nil
+	}
+	appendInt64Tag(span, receivedAtTag, int64(model.TimeAsEpochMicroseconds(s.clock())))
+	return span, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestReceivedTimestampSanitizer(t *testing.T) {
+	now := time.Unix(1500000000, 5000)
+	sanitizer := NewReceivedTimestampSanitizer(func() time.Time {
+		return now
+	})
+	expected := []*zc.BinaryAnnotation{
+		{Key: receivedAtTag, Value: int64Bytes(1500000000000005), AnnotationType: zc.AnnotationType_I64},
+	}
+
+	span, err := sanitizer.Sanitize(&zc.Span{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, span.BinaryAnnotations)
+
+	now = now.Add(time.Second)
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, expected, span.BinaryAnnotations)
+}
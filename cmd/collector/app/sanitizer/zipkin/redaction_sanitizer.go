@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"regexp"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const redactedTagsTag = "warnRedactedTags"
+
+// RedactionAction is what a RedactionRule does to the value of the tags it matches
+type RedactionAction int
+
+const (
+	// RedactionDrop replaces the value with an empty string
+	RedactionDrop RedactionAction = iota
+	// RedactionHash replaces the value with the hex encoded hash of its bytes, so that equal values stay joinable
+	RedactionHash
+	// RedactionReplace replaces the matches of Pattern within a string value with Replacement
+	RedactionReplace
+)
+
+// RedactionRule describes how to redact the values of the tags whose key matches Key
+type RedactionRule struct {
+	// Key matches the keys of the tags the rule applies to, e.g. '^db\.statement$'
+	Key *regexp.Regexp
+	// Action is what the rule does to the values of the matched tags
+	Action RedactionAction
+	// Hash returns the hash used by RedactionHash, e.g. sha256.New, which is the default if Hash is nil
+	Hash func() hash.Hash
+	// Pattern matches the parts of the value replaced by RedactionReplace, e.g. '\?.*' for URL query strings
+	Pattern *regexp.Regexp
+	// Replacement replaces the matches of Pattern, and can refer to its submatches as in regexp.ReplaceAllString
+	Replacement string
+}
+
+// NewRedactionSanitizer returns a sanitizer that redacts sensitive tag values, e.g. 'http.url' query strings,
+// 'db.statement' or 'http.request.header.authorization', before they are stored. Each binary annotation is
+// redacted by the first rule whose Key matches its key, and the keys of the redacted tags are recorded in a
+// 'warnRedactedTags' tag. Dropped and hashed values become STRING values. RedactionReplace only applies to
+// STRING values. Only the 'warnRedactedTags' tag itself is never redacted, so that application tags whose keys
+// merely look like sanitizer tags, e.g. 'errMsg', cannot escape the rules. It returns an error if a rule has
+// no Key, or is a RedactionReplace rule without a Pattern.
+func NewRedactionSanitizer(rules []RedactionRule) (Sanitizer, error) {
+	for i, rule := range rules {
+		if rule.Key == nil {
+			return nil, fmt.Errorf("redaction rule %d has no key", i)
+		}
+		if rule.Action == RedactionReplace && rule.Pattern == nil {
+			return nil, fmt.Errorf("redaction rule %d for key %q replaces values but has no pattern", i, rule.Key)
+		}
+	}
+	return &redactionSanitizer{rules: rules}, nil
+}
+
+type redactionSanitizer struct {
+	rules []RedactionRule
+}
+
+func (s *redactionSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var redacted []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Key == redactedTagsTag {
+			continue
+		}
+		for _, rule := range s.rules {
+			if !rule.Key.MatchString(binAnno.Key) {
+				continue
+			}
+			if rule.redact(binAnno) {
+				redacted = append(redacted, binAnno.Key)
+			}
+			break
+		}
+	}
+	if len(redacted) > 0 {
+		appendStringTag(span, redactedTagsTag, strings.Join(redacted, ","))
+	}
+	return span, nil
+}
+
+// redact applies the rule to the binary annotation and returns true if its value was changed.
+func (r RedactionRule) redact(binAnno *zc.BinaryAnnotation) bool {
+	switch r.Action {
+	case RedactionDrop:
+		if binAnno.AnnotationType == zc.AnnotationType_STRING && len(binAnno.Value) == 0 {
+			return false
+		}
+		binAnno.Value = []byte{}
+	case RedactionHash:
+		newHash := r.Hash
+		if newHash == nil {
+			newHash = sha256.New
+		}
+		h := newHash()
+		h.Write(binAnno.Value)
+		binAnno.Value = []byte(hex.EncodeToString(h.Sum(nil)))
+	case RedactionReplace:
+		if binAnno.AnnotationType != zc.AnnotationType_STRING {
+			return false
+		}
+		value := r.Pattern.ReplaceAllString(string(binAnno.Value), r.Replacement)
+		if value == string(binAnno.Value) {
+			return false
+		}
+		binAnno.Value = []byte(value)
+		return true
+	default:
+		return false
+	}
+	binAnno.AnnotationType = zc.AnnotationType_STRING
+	return true
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"crypto/md5"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestRedactionSanitizer(t *testing.T) {
+	sanitizer, err := NewRedactionSanitizer([]RedactionRule{
+		{Key: regexp.MustCompile(`^http\.request\.header\.`), Action: RedactionDrop},
+		{Key: regexp.MustCompile(`^db\.statement$`), Action: RedactionHash},
+		{Key: regexp.MustCompile(`^db\.user$`), Action: RedactionHash, Hash: md5.New},
+		{Key: regexp.MustCompile(`^http\.url$`), Action: RedactionReplace, Pattern: regexp.MustCompile(`\?.*`), Replacement: "?redacted"},
+		{Key: regexp.MustCompile(`url`), Action: RedactionDrop},
+	})
+	require.NoError(t, err)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("http.request.header.authorization", "Bearer secret"),
+			{Key: "http.request.header.x-id", Value: int64Bytes(42), AnnotationType: zc.AnnotationType_I64},
+			stringTag("db.statement", "SELECT 1"),
+			stringTag("db.user", "admin"),
+			stringTag("http.url", "http://example.com/login?user=admin&password=secret"),
+			stringTag("http.url", "http://example.com/"),
+			stringTag("http.method", "GET"),
+			stringTag(negativeDurationTag, "-1"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("http.request.header.authorization", ""),
+		stringTag("http.request.header.x-id", ""),
+		stringTag("db.statement", "e004ebd5b5532a4b85984a62f8ad48a81aa3460c1ca07701f386135d72cdecf5"),
+		stringTag("db.user", "21232f297a57a5a743894a0e4a801fc3"),
+		stringTag("http.url", "http://example.com/login?redacted"),
+		stringTag("http.url", "http://example.com/"),
+		stringTag("http.method", "GET"),
+		stringTag(negativeDurationTag, "-1"),
+		stringTag(redactedTagsTag, "http.request.header.authorization,http.request.header.x-id,db.statement,db.user,http.url"),
+	}, span.BinaryAnnotations)
+}
+
+func TestRedactionSanitizerNoMatch(t *testing.T) {
+	sanitizer, err := NewRedactionSanitizer([]RedactionRule{
+		{Key: regexp.MustCompile(`^db\.statement$`), Action: RedactionHash},
+	})
+	require.NoError(t, err)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("http.method", "GET")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag("http.method", "GET")}, span.BinaryAnnotations)
+}
+
+func TestRedactionSanitizerApplicationErrorTag(t *testing.T) {
+	sanitizer, err := NewRedactionSanitizer([]RedactionRule{
+		{Key: regexp.MustCompile(`.*`), Action: RedactionDrop},
+	})
+	require.NoError(t, err)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("errMsg", "password=hunter2"),
+			stringTag(redactedTagsTag, "db.statement"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("errMsg", ""),
+		stringTag(redactedTagsTag, "db.statement"),
+		stringTag(redactedTagsTag, "errMsg"),
+	}, span.BinaryAnnotations)
+}
+
+func TestRedactionSanitizerInvalidRules(t *testing.T) {
+	_, err := NewRedactionSanitizer([]RedactionRule{
+		{Key: regexp.MustCompile(`^db\.statement$`), Action: RedactionHash},
+		{Action: RedactionDrop},
+	})
+	assert.EqualError(t, err, "redaction rule 1 has no key")
+
+	_, err = NewRedactionSanitizer([]RedactionRule{
+		{Key: regexp.MustCompile(`^http\.url$`), Action: RedactionReplace, Replacement: "?redacted"},
+	})
+	assert.EqualError(t, err, `redaction rule 0 for key "^http\\.url$" replaces values but has no pattern`)
+}
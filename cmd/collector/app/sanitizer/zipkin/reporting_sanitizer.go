@@ -0,0 +1,139 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"fmt"
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// Report records a change made by a sanitizer to a span. Annotations are reported by their index, e.g.
+// 'binaryAnnotations[2]', with an empty OldValue or NewValue when they were added or removed.
+type Report struct {
+	SanitizerName string
+	Field         string
+	OldValue      string
+	NewValue      string
+}
+
+// ReportingSanitizer applies sanitizers in serial fashion like ChainedSanitizer, and can report what each of
+// them changed, e.g. for replaying a span on an admin endpoint. Reports are only built by SanitizeWithReport,
+// which copies the span before each sanitizer, so it should not be used on the hot path.
+type ReportingSanitizer struct {
+	chain ChainedSanitizer
+}
+
+// NewReportingSanitizer creates a ReportingSanitizer from the variadic list of passed Sanitizers.
+func NewReportingSanitizer(sanitizers ...Sanitizer) *ReportingSanitizer {
+	return &ReportingSanitizer{chain: NewChainedSanitizer(sanitizers...)}
+}
+
+// Sanitize calls each Sanitize, returning the first error
+func (s *ReportingSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return s.chain.Sanitize(span)
+}
+
+// SanitizeWithReport calls each Sanitize, returning the changes they made up to the first error
+func (s *ReportingSanitizer) SanitizeWithReport(span *zc.Span) (*zc.Span, []Report, error) {
+	var reports []Report
+	for _, sanitizer := range s.chain {
+		before := copySpan(span)
+		var err error
+		span, err = sanitizer.Sanitize(span)
+		reports = appendSpanDiff(reports, reportName(sanitizer), before, span)
+		if err != nil {
+			return span, reports, err
+		}
+	}
+	return span, reports, nil
+}
+
+// reportName returns the name of the sanitizer, looking through metrics wrappers.
+func reportName(s Sanitizer) string {
+	if m, ok := s.(*metricsSanitizer); ok {
+		return reportName(m.inner)
+	}
+	return sanitizerName(s)
+}
+
+func appendSpanDiff(reports []Report, name string, before, after *zc.Span) []Report {
+	diff := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			reports = append(reports, Report{SanitizerName: name, Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	diff("traceID", strconv.FormatInt(before.TraceID, 10), strconv.FormatInt(after.TraceID, 10))
+	diff("name", before.Name, after.Name)
+	diff("id", strconv.FormatInt(before.ID, 10), strconv.FormatInt(after.ID, 10))
+	diff("parentID", formatInt64Ptr(before.ParentID), formatInt64Ptr(after.ParentID))
+	diff("debug", strconv.FormatBool(before.Debug), strconv.FormatBool(after.Debug))
+	diff("timestamp", formatInt64Ptr(before.Timestamp), formatInt64Ptr(after.Timestamp))
+	diff("duration", formatInt64Ptr(before.Duration), formatInt64Ptr(after.Duration))
+	for i := 0; i < len(before.Annotations) || i < len(after.Annotations); i++ {
+		var oldValue, newValue string
+		if i < len(before.Annotations) {
+			oldValue = formatAnnotation(before.Annotations[i])
+		}
+		if i < len(after.Annotations) {
+			newValue = formatAnnotation(after.Annotations[i])
+		}
+		diff(fmt.Sprintf("annotations[%d]", i), oldValue, newValue)
+	}
+	for i := 0; i < len(before.BinaryAnnotations) || i < len(after.BinaryAnnotations); i++ {
+		var oldValue, newValue string
+		if i < len(before.BinaryAnnotations) {
+			oldValue = formatBinaryAnnotation(before.BinaryAnnotations[i])
+		}
+		if i < len(after.BinaryAnnotations) {
+			newValue = formatBinaryAnnotation(after.BinaryAnnotations[i])
+		}
+		diff(fmt.Sprintf("binaryAnnotations[%d]", i), oldValue, newValue)
+	}
+	return reports
+}
+
+func formatInt64Ptr(value *int64) string {
+	if value == nil {
+		return "nil"
+	}
+	return strconv.FormatInt(*value, 10)
+}
+
+func formatEndpoint(endpoint *zc.Endpoint) string {
+	if endpoint == nil {
+		return ""
+	}
+	return fmt.Sprintf(" @%s(%d:%d)", endpoint.ServiceName, uint32(endpoint.Ipv4), uint16(endpoint.Port))
+}
+
+func formatAnnotation(anno *zc.Annotation) string {
+	return fmt.Sprintf("%d %s%s", anno.Timestamp, anno.Value, formatEndpoint(anno.Host))
+}
+
+func formatBinaryAnnotation(binAnno *zc.BinaryAnnotation) string {
+	value, ok := valueString(binAnno)
+	if !ok {
+		value = fmt.Sprintf("%x", binAnno.Value)
+	}
+	return fmt.Sprintf("%s=%s (%s)%s", binAnno.Key, value, binAnno.AnnotationType, formatEndpoint(binAnno.Host))
+}
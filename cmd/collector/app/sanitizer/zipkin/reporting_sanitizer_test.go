@@ -0,0 +1,87 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestReportingSanitizer(t *testing.T) {
+	zero := int64(0)
+	sanitizer := NewReportingSanitizer(
+		NewSpanDurationSanitizer(zap.NewNop()),
+		NewParentIDSanitizer(zap.NewNop()),
+		NewErrorTagSanitizer(),
+	)
+	span := &zc.Span{
+		ParentID:          &zero,
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("error", "true")},
+	}
+	span, reports, err := sanitizer.SanitizeWithReport(span)
+	require.NoError(t, err)
+	assert.Nil(t, span.ParentID)
+	assert.Equal(t, []Report{
+		{SanitizerName: "spanDurationSanitizer", Field: "duration", OldValue: "nil", NewValue: "1"},
+		{SanitizerName: "parentIDSanitizer", Field: "parentID", OldValue: "0", NewValue: "nil"},
+		{SanitizerName: "parentIDSanitizer", Field: "binaryAnnotations[1]", NewValue: "errZeroParentID=0 (STRING)"},
+		{SanitizerName: "errorTagSanitizer", Field: "binaryAnnotations[0]", OldValue: "error=true (STRING)", NewValue: "error=true (BOOL)"},
+	}, reports)
+}
+
+func TestReportingSanitizerError(t *testing.T) {
+	host := &zc.Endpoint{ServiceName: "frontend", Ipv4: 1, Port: 80}
+	sanitizer := NewReportingSanitizer(
+		NewChainedSanitizerWithMetrics(metrics.NullFactory, NewAnnotationTimestampInterpolationSanitizer()).ChainedSanitizer[0],
+		failingSanitizer{},
+		NewSpanDurationSanitizer(zap.NewNop()),
+	)
+	timestamp := int64(100)
+	span := &zc.Span{Timestamp: &timestamp, Annotations: []*zc.Annotation{{Value: "event", Host: host}}}
+	span, reports, err := sanitizer.SanitizeWithReport(span)
+	assert.Equal(t, errFailingSanitizer, err)
+	assert.Nil(t, span.Duration)
+	assert.Equal(t, []Report{
+		{
+			SanitizerName: "annotationTimestampInterpolationSanitizer",
+			Field:         "annotations[0]",
+			OldValue:      "0 event @frontend(1:80)",
+			NewValue:      "100 event @frontend(1:80)",
+		},
+		{
+			SanitizerName: "annotationTimestampInterpolationSanitizer",
+			Field:         "binaryAnnotations[0]",
+			NewValue:      "warnInterpolatedAnnotationTimestamp=1 (STRING)",
+		},
+	}, reports)
+}
+
+func TestReportingSanitizerSanitize(t *testing.T) {
+	span, err := NewReportingSanitizer(NewSpanDurationSanitizer(zap.NewNop())).Sanitize(&zc.Span{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), *span.Duration)
+}
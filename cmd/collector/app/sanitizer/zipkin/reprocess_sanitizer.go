@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"context"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	sanitizedStampTag = "sanitized"
+	reprocessedTag    = "warnReprocessed"
+)
+
+// NewReprocessDetectionSanitizer returns a sanitizer that stamps spans with a 'sanitized' tag,
+// and tags spans that already carry the stamp with 'warnReprocessed', e.g. when they are
+// replayed from a dead-letter queue. It should be the first stage of the chain.
+func NewReprocessDetectionSanitizer() Sanitizer {
+	return &reprocessDetectionSanitizer{}
+}
+
+type reprocessDetectionSanitizer struct {
+}
+
+func (s *reprocessDetectionSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if findBinaryAnnotation(span, sanitizedStampTag) == nil {
+		appendStringTag(span, sanitizedStampTag, "true")
+	} else if findBinaryAnnotation(span, reprocessedTag) == nil {
+		appendStringTag(span, reprocessedTag, "true")
+	}
+	return span, nil
+}
+
+// NewSkipReprocessedSanitizer wraps a non-idempotent sanitizer so that it is not applied again
+// to spans flagged by the reprocess detection sanitizer.
+//
+// The 'warnReprocessed' tag serves as the flag rather than a context value: ChainedSanitizer passes
+// the same context to every stage, so a stage cannot flag the span for the stages that follow it,
+// whereas the tag travels with the span, even through chains built without context support.
+// The wrapped sanitizer still receives the context if it implements ContextSanitizer.
+func NewSkipReprocessedSanitizer(sanitizer Sanitizer) Sanitizer {
+	return &skipReprocessedSanitizer{sanitizer: sanitizer}
+}
+
+type skipReprocessedSanitizer struct {
+	sanitizer Sanitizer
+}
+
+func (s *skipReprocessedSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return s.SanitizeCtx(context.Background(), span)
+}
+
+func (s *skipReprocessedSanitizer) SanitizeCtx(ctx context.Context, span *zc.Span) (*zc.Span, error) {
+	if findBinaryAnnotation(span, reprocessedTag) != nil {
+		return span, nil
+	}
+	return sanitizeCtx(ctx, s.sanitizer, span)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestReprocessDetectionSanitizer(t *testing.T) {
+	sanitizer := NewChainedSanitizer(
+		NewReprocessDetectionSanitizer(),
+		NewSkipReprocessedSanitizer(NewSpanDurationSanitizer(zap.NewNop())),
+	)
+	duration := int64(-1)
+	span, err := sanitizer.Sanitize(&zc.Span{Duration: &duration})
+	require.NoError(t, err)
+	assert.Nil(t, findBinaryAnnotation(span, reprocessedTag))
+	assert.NotNil(t, findBinaryAnnotation(span, sanitizedStampTag))
+	assert.Len(t, span.BinaryAnnotations, 2)
+
+	duration = -2
+	span.Duration = &duration
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.NotNil(t, findBinaryAnnotation(span, reprocessedTag))
+	assert.Equal(t, int64(-2), *span.Duration, "duration sanitizer must be skipped")
+	assert.Len(t, span.BinaryAnnotations, 3)
+
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 3, "reprocessed tag is only added once")
+}
+
+func TestSkipReprocessedSanitizerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling := &cancellingSanitizer{cancel: cancel}
+	sanitizer := NewSkipReprocessedSanitizer(cancelling).(ContextSanitizer)
+
+	span, err := sanitizer.SanitizeCtx(ctx, &zc.Span{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, cancelling.calls)
+
+	appendStringTag(span, reprocessedTag, "true")
+	_, err = sanitizer.SanitizeCtx(ctx, span)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cancelling.calls)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	badSamplerTagsTag = "warnBadSamplerTags"
+	samplerTypeKey    = "sampler.type"
+	samplerParamKey   = "sampler.param"
+
+	samplerTypeConst         = "const"
+	samplerTypeProbabilistic = "probabilistic"
+	samplerTypeRateLimiting  = "ratelimiting"
+	samplerTypeLowerBound    = "lowerbound"
+)
+
+// NewSamplerTagSanitizer returns a sanitizer that validates the 'sampler.type' and 'sampler.param' tags
+// set by Jaeger clients. The type must be one of const, probabilistic, ratelimiting or lowerbound, and is
+// normalized to lower case. The param is converted to BOOL for the const sampler, and to DOUBLE for the
+// others, e.g. when it was reported as a string. Violations, such as an unknown type, an unparsable param
+// or a probability outside of [0, 1], are recorded in a 'warnBadSamplerTags' tag.
+func NewSamplerTagSanitizer() Sanitizer {
+	return &samplerTagSanitizer{}
+}
+
+type samplerTagSanitizer struct {
+}
+
+func (s *samplerTagSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	samplerType := findBinaryAnnotation(span, samplerTypeKey)
+	if samplerType == nil {
+		return span, nil
+	}
+	var violations []string
+	typ := strings.ToLower(strings.TrimSpace(string(samplerType.Value)))
+	switch typ {
+	case samplerTypeConst, samplerTypeProbabilistic, samplerTypeRateLimiting, samplerTypeLowerBound:
+		samplerType.Value = []byte(typ)
+		samplerType.AnnotationType = zc.AnnotationType_STRING
+	default:
+		violations = append(violations, "type:"+string(samplerType.Value))
+	}
+	if param := findBinaryAnnotation(span, samplerParamKey); param != nil && len(violations) == 0 {
+		if !coerceSamplerParam(typ, param) {
+			text, _ := valueString(param)
+			violations = append(violations, "param:"+text)
+		}
+	}
+	if len(violations) > 0 {
+		appendStringTag(span, badSamplerTagsTag, strings.Join(violations, ","))
+	}
+	return span, nil
+}
+
+// coerceSamplerParam converts the param to the type used by the given sampler type,
+// and returns false if the param cannot be converted or is out of range.
+func coerceSamplerParam(samplerType string, param *zc.BinaryAnnotation) bool {
+	if samplerType == samplerTypeConst {
+		var value bool
+		switch param.AnnotationType {
+		case zc.AnnotationType_BOOL:
+			return len(param.Value) == 1
+		case zc.AnnotationType_STRING:
+			v, err := strconv.ParseBool(strings.TrimSpace(string(param.Value)))
+			if err != nil {
+				return false
+			}
+			value = v
+		case zc.AnnotationType_I16, zc.AnnotationType_I32, zc.AnnotationType_I64:
+			v, err := decodeInt(param)
+			if err != nil || (v != 0 && v != 1) {
+				return false
+			}
+			value = v == 1
+		default:
+			return false
+		}
+		param.AnnotationType = zc.AnnotationType_BOOL
+		param.Value = []byte{0}
+		if value {
+			param.Value = []byte{1}
+		}
+		return true
+	}
+	var value float64
+	switch param.AnnotationType {
+	case zc.AnnotationType_DOUBLE:
+		v, err := decodeDouble(param)
+		if err != nil {
+			return false
+		}
+		value = v
+	case zc.AnnotationType_STRING:
+		v, err := strconv.ParseFloat(strings.TrimSpace(string(param.Value)), 64)
+		if err != nil {
+			return false
+		}
+		value = v
+	case zc.AnnotationType_I16, zc.AnnotationType_I32, zc.AnnotationType_I64:
+		v, err := decodeInt(param)
+		if err != nil {
+			return false
+		}
+		value = float64(v)
+	default:
+		return false
+	}
+	if value < 0 || (value > 1 && samplerType != samplerTypeRateLimiting) {
+		return false
+	}
+	param.AnnotationType = zc.AnnotationType_DOUBLE
+	param.Value = float64Bytes(value)
+	return true
+}
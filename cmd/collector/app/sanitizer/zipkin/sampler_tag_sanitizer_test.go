@@ -0,0 +1,99 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestSamplerTagSanitizer(t *testing.T) {
+	tests := []struct {
+		samplerType   string
+		param         *zc.BinaryAnnotation
+		expectedType  string
+		expectedParam *zc.BinaryAnnotation
+		violations    string
+	}{
+		{
+			samplerType:   "const",
+			param:         stringTag(samplerParamKey, "true"),
+			expectedType:  "const",
+			expectedParam: &zc.BinaryAnnotation{Key: samplerParamKey, Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL},
+		},
+		{
+			samplerType:   "const",
+			param:         &zc.BinaryAnnotation{Key: samplerParamKey, Value: int64Bytes(0), AnnotationType: zc.AnnotationType_I64},
+			expectedType:  "const",
+			expectedParam: &zc.BinaryAnnotation{Key: samplerParamKey, Value: []byte{0}, AnnotationType: zc.AnnotationType_BOOL},
+		},
+		{
+			samplerType:   " Probabilistic",
+			param:         stringTag(samplerParamKey, "0.25"),
+			expectedType:  "probabilistic",
+			expectedParam: &zc.BinaryAnnotation{Key: samplerParamKey, Value: float64Bytes(0.25), AnnotationType: zc.AnnotationType_DOUBLE},
+		},
+		{
+			samplerType:   "probabilistic",
+			param:         stringTag(samplerParamKey, "1.5"),
+			expectedType:  "probabilistic",
+			expectedParam: stringTag(samplerParamKey, "1.5"),
+			violations:    "param:1.5",
+		},
+		{
+			samplerType:   "ratelimiting",
+			param:         &zc.BinaryAnnotation{Key: samplerParamKey, Value: []byte{0, 0, 0, 5}, AnnotationType: zc.AnnotationType_I32},
+			expectedType:  "ratelimiting",
+			expectedParam: &zc.BinaryAnnotation{Key: samplerParamKey, Value: float64Bytes(5), AnnotationType: zc.AnnotationType_DOUBLE},
+		},
+		{
+			samplerType:   "lowerbound",
+			param:         &zc.BinaryAnnotation{Key: samplerParamKey, Value: float64Bytes(0.001), AnnotationType: zc.AnnotationType_DOUBLE},
+			expectedType:  "lowerbound",
+			expectedParam: &zc.BinaryAnnotation{Key: samplerParamKey, Value: float64Bytes(0.001), AnnotationType: zc.AnnotationType_DOUBLE},
+		},
+		{
+			samplerType:   "probabilistik",
+			param:         stringTag(samplerParamKey, "0.5"),
+			expectedType:  "probabilistik",
+			expectedParam: stringTag(samplerParamKey, "0.5"),
+			violations:    "type:probabilistik",
+		},
+	}
+	sanitizer := NewSamplerTagSanitizer()
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{
+			BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(samplerTypeKey, test.samplerType), test.param},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, test.expectedType, string(span.BinaryAnnotations[0].Value), test.samplerType)
+		assert.Equal(t, test.expectedParam, span.BinaryAnnotations[1], test.samplerType)
+		if test.violations == "" {
+			assert.Len(t, span.BinaryAnnotations, 2, test.samplerType)
+		} else if assert.Len(t, span.BinaryAnnotations, 3, test.samplerType) {
+			assert.Equal(t, stringTag(badSamplerTagsTag, test.violations), span.BinaryAnnotations[2])
+		}
+	}
+}
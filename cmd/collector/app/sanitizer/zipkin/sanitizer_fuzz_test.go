@@ -0,0 +1,115 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build go1.18
+// +build go1.18
+
+package zipkin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// boundedProtocol rejects containers with more elements than there are bytes left in the input, for which
+// the generated code would preallocate, or skip, up to 2^31 elements.
+type boundedProtocol struct {
+	thrift.TProtocol
+	buffer *thrift.TMemoryBuffer
+}
+
+var errContainerTooLong = thrift.NewTProtocolException(errors.New("container longer than input"))
+
+func (p boundedProtocol) ReadListBegin() (thrift.TType, int, error) {
+	elemType, size, err := p.TProtocol.ReadListBegin()
+	if err == nil && size > p.buffer.Len() {
+		err = errContainerTooLong
+	}
+	return elemType, size, err
+}
+
+func (p boundedProtocol) ReadSetBegin() (thrift.TType, int, error) {
+	elemType, size, err := p.TProtocol.ReadSetBegin()
+	if err == nil && size > p.buffer.Len() {
+		err = errContainerTooLong
+	}
+	return elemType, size, err
+}
+
+func (p boundedProtocol) ReadMapBegin() (thrift.TType, thrift.TType, int, error) {
+	keyType, valueType, size, err := p.TProtocol.ReadMapBegin()
+	if err == nil && size > p.buffer.Len() {
+		err = errContainerTooLong
+	}
+	return keyType, valueType, size, err
+}
+
+// Skip skips through boundedProtocol rather than the wrapped protocol.
+func (p boundedProtocol) Skip(fieldType thrift.TType) error {
+	return thrift.SkipDefaultDepth(p, fieldType)
+}
+
+func FuzzSanitize(f *testing.F) {
+	zero, negative := int64(0), int64(-1)
+	seeds := []*zc.Span{
+		{},
+		{ParentID: &zero},
+		{ID: 1, ParentID: &zero, Duration: &negative},
+		{Annotations: []*zc.Annotation{}, BinaryAnnotations: []*zc.BinaryAnnotation{}},
+		{Annotations: []*zc.Annotation{{Value: zc.CLIENT_SEND}}},
+		{BinaryAnnotations: []*zc.BinaryAnnotation{{Key: "error", AnnotationType: zc.AnnotationType_BOOL}}},
+		{BinaryAnnotations: []*zc.BinaryAnnotation{{Key: "error", Value: []byte("x"), AnnotationType: zc.AnnotationType_I64}}},
+	}
+	for _, seed := range seeds {
+		buffer := thrift.NewTMemoryBuffer()
+		if err := seed.Write(thrift.NewTBinaryProtocolTransport(buffer)); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buffer.Bytes())
+	}
+	sanitizer := NewSanitizerChainFromOptions(Options{
+		EnableDuration:      true,
+		EnableSelfReference: true,
+		EnableParentID:      true,
+		EnableErrorTag:      true,
+		MaxDuration:         time.Hour,
+		MaxTagCount:         10,
+		MaxTagValueLength:   16,
+	}, zap.NewNop(), metrics.NullFactory)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		buffer := thrift.NewTMemoryBuffer()
+		buffer.Write(data)
+		span := &zc.Span{}
+		if err := span.Read(boundedProtocol{TProtocol: thrift.NewTBinaryProtocolTransport(buffer), buffer: buffer}); err != nil {
+			return
+		}
+		if actual, _ := sanitizer.Sanitize(span); actual == nil {
+			t.Fatal("sanitizer returned a nil span")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const schemaViolationTag = "warnSchemaViolation"
+
+// ServiceSchema describes the tags expected on the spans of a service.
+type ServiceSchema struct {
+	// RequiredKeys are the tag keys that must be present on every span.
+	RequiredKeys []string
+	// ForbiddenKeys are the tag keys that must not be present on any span.
+	ForbiddenKeys []string
+	// ValuePatterns maps tag keys to the pattern their string values must match.
+	ValuePatterns map[string]*regexp.Regexp
+	// Strict makes NewSchemaValidationFilter drop the spans violating the schema.
+	Strict bool
+}
+
+// validate returns the list of violations of the schema by the span, e.g. "missing:http.method".
+func (s ServiceSchema) validate(span *zc.Span) []string {
+	var violations []string
+	for _, key := range s.RequiredKeys {
+		if findBinaryAnnotation(span, key) == nil {
+			violations = append(violations, "missing:"+key)
+		}
+	}
+	for _, key := range s.ForbiddenKeys {
+		if findBinaryAnnotation(span, key) != nil {
+			violations = append(violations, "forbidden:"+key)
+		}
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		pattern, ok := s.ValuePatterns[binAnno.Key]
+		if ok && binAnno.AnnotationType == zc.AnnotationType_STRING && !pattern.Match(binAnno.Value) {
+			violations = append(violations, "pattern:"+binAnno.Key)
+		}
+	}
+	return violations
+}
+
+// NewSchemaValidationSanitizer returns a sanitizer that validates the tags of spans against the schema
+// of the span's service, and tags the violations in a 'warnSchemaViolation' tag.
+// Spans of services without a schema are left untouched.
+func NewSchemaValidationSanitizer(schemas map[string]ServiceSchema) Sanitizer {
+	return &schemaValidationSanitizer{schemas: schemas}
+}
+
+type schemaValidationSanitizer struct {
+	schemas map[string]ServiceSchema
+}
+
+func (s *schemaValidationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	schema, ok := s.schemas[findServiceName(span)]
+	if !ok {
+		return span, nil
+	}
+	if violations := schema.validate(span); len(violations) > 0 {
+		appendStringTag(span, schemaViolationTag, strings.Join(violations, ","))
+	}
+	return span, nil
+}
+
+// NewSchemaValidationFilter returns a batch sanitizer that drops the spans violating the schema
+// of their service, for the services whose schema is Strict.
+func NewSchemaValidationFilter(schemas map[string]ServiceSchema, logger *zap.Logger, metricsFactory metrics.Factory) BatchSanitizer {
+	return &schemaValidationFilter{
+		schemas: schemas,
+		log:     spanLogger{logger},
+		dropped: metricsFactory.Counter("spans.dropped", map[string]string{"reason": "schema-violation"}),
+	}
+}
+
+type schemaValidationFilter struct {
+	schemas map[string]ServiceSchema
+	log     spanLogger
+	dropped metrics.Counter
+}
+
+func (f *schemaValidationFilter) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	return filterSpans(spans, f.keep)
+}
+
+func (f *schemaValidationFilter) keep(span *zc.Span) bool {
+	schema, ok := f.schemas[findServiceName(span)]
+	if !ok || !schema.Strict {
+		return true
+	}
+	violations := schema.validate(span)
+	if len(violations) == 0 {
+		return true
+	}
+	f.log.ForSpan(span).Warn("Dropping span violating service schema", zap.Strings("violations", violations))
+	f.dropped.Inc(1)
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+var testSchemas = map[string]ServiceSchema{
+	"checkout": {
+		RequiredKeys:  []string{"http.method"},
+		ForbiddenKeys: []string{"user.email"},
+		ValuePatterns: map[string]*regexp.Regexp{"http.status_code": regexp.MustCompile(`^[1-5][0-9][0-9]$`)},
+		Strict:        true,
+	},
+}
+
+func schemaTestSpan(service string, tags ...*zc.BinaryAnnotation) *zc.Span {
+	return &zc.Span{
+		Annotations:       []*zc.Annotation{{Value: zc.SERVER_RECV, Host: &zc.Endpoint{ServiceName: service}}},
+		BinaryAnnotations: tags,
+	}
+}
+
+func TestSchemaValidationSanitizer(t *testing.T) {
+	tests := []struct {
+		span       *zc.Span
+		violations string
+	}{
+		{
+			span: schemaTestSpan("checkout", stringTag("http.method", "GET"), stringTag("http.status_code", "200")),
+		},
+		{
+			span: schemaTestSpan("other", stringTag("user.email", "x@example.com")),
+		},
+		{
+			span:       schemaTestSpan("checkout", stringTag("user.email", "x@example.com"), stringTag("http.status_code", "OK")),
+			violations: "missing:http.method,forbidden:user.email,pattern:http.status_code",
+		},
+	}
+	sanitizer := NewSchemaValidationSanitizer(testSchemas)
+	for _, test := range tests {
+		tagCount := len(test.span.BinaryAnnotations)
+		span, err := sanitizer.Sanitize(test.span)
+		require.NoError(t, err)
+		if test.violations == "" {
+			assert.Len(t, span.BinaryAnnotations, tagCount)
+			continue
+		}
+		tag := findBinaryAnnotation(span, schemaViolationTag)
+		if assert.NotNil(t, tag) {
+			assert.Equal(t, test.violations, string(tag.Value))
+		}
+	}
+}
+
+func TestSchemaValidationFilter(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	filter := NewSchemaValidationFilter(testSchemas, zap.NewNop(), metricsFactory)
+	valid := schemaTestSpan("checkout", stringTag("http.method", "GET"))
+	invalid := schemaTestSpan("checkout")
+	other := schemaTestSpan("other")
+
+	assert.Equal(t, []*zc.Span{valid, other}, filter.SanitizeBatch([]*zc.Span{valid, invalid, other}))
+	counters, _ := metricsFactory.Snapshot()
+	assert.EqualValues(t, 1, counters["spans.dropped|reason=schema-violation"])
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const searchStringSuffix = ".str"
+
+// NewSearchNormalizeSanitizer returns a sanitizer that makes typed tags searchable as text.
+// For each of the given keys with a non-string value, e.g. a BOOL 'error' tag, it adds a companion
+// STRING tag '<key>.str' holding the textual value, e.g. 'error.str' = "true". The typed tag is left
+// intact so that typed queries keep working, and text queries can use the companion tag.
+func NewSearchNormalizeSanitizer(keys []string) Sanitizer {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[key] = struct{}{}
+	}
+	return &searchNormalizeSanitizer{keys: keySet}
+}
+
+type searchNormalizeSanitizer struct {
+	keys map[string]struct{}
+}
+
+func (s *searchNormalizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	for _, binAnno := range span.BinaryAnnotations {
+		if _, ok := s.keys[binAnno.Key]; !ok || binAnno.AnnotationType == zc.AnnotationType_STRING {
+			continue
+		}
+		companionKey := binAnno.Key + searchStringSuffix
+		if findBinaryAnnotation(span, companionKey) != nil {
+			continue
+		}
+		if value, ok := valueString(binAnno); ok {
+			appendStringTag(span, companionKey, value)
+		}
+	}
+	return span, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestSearchNormalizeSanitizer(t *testing.T) {
+	sanitizer := NewSearchNormalizeSanitizer([]string{"error", "retries", "http.method"})
+	span := &zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: "error", Value: []byte{1}, AnnotationType: zc.AnnotationType_BOOL},
+			{Key: "retries", Value: []byte{0, 3}, AnnotationType: zc.AnnotationType_I16},
+			{Key: "other", Value: int64Bytes(5), AnnotationType: zc.AnnotationType_I64},
+			stringTag("http.method", "GET"),
+		},
+	}
+	span, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	if assert.Len(t, span.BinaryAnnotations, 6) {
+		assert.Equal(t, stringTag("error.str", "true"), span.BinaryAnnotations[4])
+		assert.Equal(t, stringTag("retries.str", "3"), span.BinaryAnnotations[5])
+	}
+}
+
+func TestValueString(t *testing.T) {
+	tests := []struct {
+		binAnno  *zc.BinaryAnnotation
+		expected string
+		ok       bool
+	}{
+		{&zc.BinaryAnnotation{Value: []byte{0}, AnnotationType: zc.AnnotationType_BOOL}, "false", true},
+		{&zc.BinaryAnnotation{Value: []byte{0xff, 0xfe}, AnnotationType: zc.AnnotationType_I16}, "-2", true},
+		{&zc.BinaryAnnotation{Value: []byte{0, 0, 1, 0}, AnnotationType: zc.AnnotationType_I32}, "256", true},
+		{&zc.BinaryAnnotation{Value: int64Bytes(-7), AnnotationType: zc.AnnotationType_I64}, "-7", true},
+		{&zc.BinaryAnnotation{Value: []byte{0x3f, 0xf8, 0, 0, 0, 0, 0, 0}, AnnotationType: zc.AnnotationType_DOUBLE}, "1.5", true},
+		{&zc.BinaryAnnotation{Value: []byte("x"), AnnotationType: zc.AnnotationType_STRING}, "x", true},
+		{&zc.BinaryAnnotation{Value: []byte{1, 2, 3}, AnnotationType: zc.AnnotationType_I32}, "", false},
+		{&zc.BinaryAnnotation{Value: []byte{1}, AnnotationType: zc.AnnotationType_DOUBLE}, "", false},
+		{&zc.BinaryAnnotation{Value: []byte{1}, AnnotationType: zc.AnnotationType_BYTES}, "", false},
+	}
+	for _, test := range tests {
+		actual, ok := valueString(test.binAnno)
+		assert.Equal(t, test.ok, ok, test.binAnno.AnnotationType.String())
+		assert.Equal(t, test.expected, actual, test.binAnno.AnnotationType.String())
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const serviceDisplayNameKey = "service.display_name"
+
+// NewServiceDisplayNameSanitizer returns a sanitizer that normalizes the service names of all endpoints
+// of a span, by lowercasing them, trimming them and collapsing whitespace, so that they group consistently
+// in storage. The original service name is preserved in a 'service.display_name' tag for display.
+func NewServiceDisplayNameSanitizer() Sanitizer {
+	return &serviceDisplayNameSanitizer{}
+}
+
+type serviceDisplayNameSanitizer struct {
+}
+
+func (s *serviceDisplayNameSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	displayName := findServiceName(span)
+	changed := false
+	normalize := func(endpoint *zc.Endpoint) {
+		if endpoint == nil {
+			return
+		}
+		if name := normalizeServiceDisplayName(endpoint.ServiceName); name != endpoint.ServiceName {
+			endpoint.ServiceName = name
+			changed = true
+		}
+	}
+	for _, anno := range span.Annotations {
+		normalize(anno.Host)
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		normalize(binAnno.Host)
+	}
+	if changed && findBinaryAnnotation(span, serviceDisplayNameKey) == nil {
+		appendStringTag(span, serviceDisplayNameKey, displayName)
+	}
+	return span, nil
+}
+
+func normalizeServiceDisplayName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestServiceDisplayNameSanitizer(t *testing.T) {
+	sanitizer := NewServiceDisplayNameSanitizer()
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.SERVER_RECV, Host: &zc.Endpoint{ServiceName: " Order  Service"}},
+			{Value: zc.SERVER_SEND, Host: &zc.Endpoint{ServiceName: "order service"}},
+			{Value: "event"},
+		},
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			{Key: zc.CLIENT_ADDR, Host: &zc.Endpoint{ServiceName: "ORDER SERVICE "}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "order service", span.Annotations[0].Host.ServiceName)
+	assert.Equal(t, "order service", span.Annotations[1].Host.ServiceName)
+	assert.Equal(t, "order service", span.BinaryAnnotations[0].Host.ServiceName)
+	assert.Equal(t, stringTag(serviceDisplayNameKey, " Order  Service"), span.BinaryAnnotations[1])
+
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 2)
+}
+
+func TestServiceDisplayNameSanitizerAlreadyNormalized(t *testing.T) {
+	span, err := NewServiceDisplayNameSanitizer().Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV, Host: &zc.Endpoint{ServiceName: "order service"}}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, span.BinaryAnnotations)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+	"unicode"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// ServiceNameNormalizationOptions selects the transforms applied by the service name normalization sanitizer.
+type ServiceNameNormalizationOptions struct {
+	// Trim removes leading and trailing whitespace, e.g. 'my service ' becomes 'my service'
+	Trim bool
+	// LowerCase converts to lower case, e.g. 'My Service' becomes 'my service'
+	LowerCase bool
+	// ReplaceWhitespace replaces each run of whitespace with a dash, e.g. 'my  service' becomes 'my-service'
+	ReplaceWhitespace bool
+}
+
+// NewServiceNameNormalizationSanitizer returns a sanitizer that normalizes the service names of all the
+// endpoints of a span, so that e.g. 'My Service' and 'my-service ' are the same service. The transforms
+// are applied in the order of the fields of opts.
+func NewServiceNameNormalizationSanitizer(opts ServiceNameNormalizationOptions) Sanitizer {
+	if opts == (ServiceNameNormalizationOptions{}) {
+		return NewNoopSanitizer()
+	}
+	return &serviceNameNormalizationSanitizer{opts: opts}
+}
+
+type serviceNameNormalizationSanitizer struct {
+	opts ServiceNameNormalizationOptions
+}
+
+func (s *serviceNameNormalizationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	for _, anno := range span.Annotations {
+		s.normalize(anno.Host)
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		s.normalize(binAnno.Host)
+	}
+	return span, nil
+}
+
+func (s *serviceNameNormalizationSanitizer) normalize(endpoint *zc.Endpoint) {
+	if endpoint == nil {
+		return
+	}
+	name := endpoint.ServiceName
+	if s.opts.Trim {
+		name = strings.TrimSpace(name)
+	}
+	if s.opts.LowerCase {
+		name = strings.ToLower(name)
+	}
+	if s.opts.ReplaceWhitespace {
+		name = replaceWhitespace(name)
+	}
+	endpoint.ServiceName = name
+}
+
+// replaceWhitespace replaces each run of whitespace in the value with a single dash.
+func replaceWhitespace(value string) string {
+	if strings.IndexFunc(value, unicode.IsSpace) < 0 {
+		return value
+	}
+	replaced := make([]rune, 0, len(value))
+	inSpace := false
+	for _, r := range value {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				replaced = append(replaced, '-')
+			}
+			inSpace = true
+			continue
+		}
+		replaced = append(replaced, r)
+		inSpace = false
+	}
+	return string(replaced)
+}
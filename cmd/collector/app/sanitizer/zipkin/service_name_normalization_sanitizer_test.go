@@ -0,0 +1,71 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestServiceNameNormalizationSanitizer(t *testing.T) {
+	all := ServiceNameNormalizationOptions{Trim: true, LowerCase: true, ReplaceWhitespace: true}
+	tests := []struct {
+		opts     ServiceNameNormalizationOptions
+		input    string
+		expected string
+	}{
+		{all, "My Service", "my-service"},
+		{all, "my service ", "my-service"},
+		{all, "my-service", "my-service"},
+		{all, " My \t Service\n", "my-service"},
+		{all, "", ""},
+		{ServiceNameNormalizationOptions{Trim: true}, " My Service ", "My Service"},
+		{ServiceNameNormalizationOptions{LowerCase: true}, " My Service ", " my service "},
+		{ServiceNameNormalizationOptions{ReplaceWhitespace: true}, " My  Service ", "-My-Service-"},
+		{ServiceNameNormalizationOptions{}, " My Service ", " My Service "},
+	}
+	for _, test := range tests {
+		shared := &zc.Endpoint{ServiceName: test.input}
+		span := &zc.Span{
+			Annotations: []*zc.Annotation{
+				{Value: zc.SERVER_RECV, Host: shared},
+				{Value: zc.SERVER_SEND, Host: shared},
+				{Value: "event"},
+			},
+			BinaryAnnotations: []*zc.BinaryAnnotation{
+				{Key: zc.CLIENT_ADDR, Host: &zc.Endpoint{ServiceName: test.input}},
+			},
+		}
+		span, err := NewServiceNameNormalizationSanitizer(test.opts).Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.Annotations[0].Host.ServiceName, "%+v %q", test.opts, test.input)
+		assert.Equal(t, test.expected, span.BinaryAnnotations[0].Host.ServiceName, "%+v %q", test.opts, test.input)
+	}
+}
+
+func TestServiceNameNormalizationSanitizerDisabled(t *testing.T) {
+	sanitizer := NewServiceNameNormalizationSanitizer(ServiceNameNormalizationOptions{})
+	assert.Equal(t, NewNoopSanitizer(), sanitizer)
+}
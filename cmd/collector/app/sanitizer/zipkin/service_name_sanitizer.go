@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const missingServiceNameTag = "errMissingServiceName"
+
+// NewServiceNameSanitizer returns a sanitizer that fills in missing endpoint service names. If an endpoint
+// of the span has a service name, it is copied to the endpoints without one, and annotations without an
+// endpoint are given one with that service name. If none has, defaultService is used instead, and the span
+// is tagged with 'errMissingServiceName', itself attached to an endpoint with that service name.
+func NewServiceNameSanitizer(defaultService string) Sanitizer {
+	return &serviceNameSanitizer{defaultService: defaultService}
+}
+
+type serviceNameSanitizer struct {
+	defaultService string
+}
+
+func (s *serviceNameSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if service := findServiceName(span); service != "" {
+		s.setServiceName(span, service, &zc.Endpoint{ServiceName: service})
+		return span, nil
+	}
+	host := &zc.Endpoint{ServiceName: s.defaultService}
+	s.setServiceName(span, s.defaultService, host)
+	appendStringTag(span, missingServiceNameTag, s.defaultService)
+	span.BinaryAnnotations[len(span.BinaryAnnotations)-1].Host = host
+	return span, nil
+}
+
+// setServiceName sets the service name of the endpoints without one, and sets host as the endpoint of the
+// annotations without one.
+func (s *serviceNameSanitizer) setServiceName(span *zc.Span, service string, host *zc.Endpoint) {
+	set := func(endpoint **zc.Endpoint) {
+		if *endpoint == nil {
+			*endpoint = host
+		} else if (*endpoint).ServiceName == "" {
+			(*endpoint).ServiceName = service
+		}
+	}
+	for _, anno := range span.Annotations {
+		set(&anno.Host)
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		set(&binAnno.Host)
+	}
+}
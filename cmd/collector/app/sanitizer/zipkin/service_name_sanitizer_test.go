@@ -0,0 +1,90 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestServiceNameSanitizerAllMissing(t *testing.T) {
+	unnamed := &zc.Endpoint{Ipv4: 1}
+	span := &zc.Span{
+		Annotations:       []*zc.Annotation{{Value: zc.CLIENT_SEND, Host: unnamed}, {Value: zc.CLIENT_RECV}},
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("key", "value")},
+	}
+	span, err := NewServiceNameSanitizer("unknown-service").Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, &zc.Endpoint{Ipv4: 1, ServiceName: "unknown-service"}, span.Annotations[0].Host)
+	assert.Equal(t, &zc.Endpoint{ServiceName: "unknown-service"}, span.Annotations[1].Host)
+	assert.Equal(t, &zc.Endpoint{ServiceName: "unknown-service"}, span.BinaryAnnotations[0].Host)
+	if assert.Len(t, span.BinaryAnnotations, 2) {
+		tag := span.BinaryAnnotations[1]
+		assert.Equal(t, missingServiceNameTag, tag.Key)
+		assert.Equal(t, "unknown-service", string(tag.Value))
+		assert.True(t, span.Annotations[1].Host == tag.Host)
+	}
+}
+
+func TestServiceNameSanitizerNoAnnotations(t *testing.T) {
+	span, err := NewServiceNameSanitizer("unknown-service").Sanitize(&zc.Span{})
+	require.NoError(t, err)
+	if assert.Len(t, span.BinaryAnnotations, 1) {
+		assert.Equal(t, &zc.Endpoint{ServiceName: "unknown-service"}, span.BinaryAnnotations[0].Host)
+	}
+	assert.Equal(t, "unknown-service", findServiceName(span))
+}
+
+func TestServiceNameSanitizerPartiallyMissing(t *testing.T) {
+	unnamed := &zc.Endpoint{Ipv4: 1}
+	span := &zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.SERVER_RECV, Host: unnamed},
+			{Value: zc.SERVER_SEND, Host: &zc.Endpoint{ServiceName: "backend"}},
+			{Value: "event"},
+		},
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("key", "value")},
+	}
+	span, err := NewServiceNameSanitizer("unknown-service").Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, &zc.Endpoint{Ipv4: 1, ServiceName: "backend"}, span.Annotations[0].Host)
+	assert.Equal(t, &zc.Endpoint{ServiceName: "backend"}, span.Annotations[2].Host)
+	if assert.Len(t, span.BinaryAnnotations, 1) {
+		assert.Equal(t, &zc.Endpoint{ServiceName: "backend"}, span.BinaryAnnotations[0].Host)
+	}
+}
+
+func TestServiceNameSanitizerPopulated(t *testing.T) {
+	host := &zc.Endpoint{ServiceName: "frontend"}
+	span := &zc.Span{
+		Annotations:       []*zc.Annotation{{Value: zc.CLIENT_SEND, Host: host}},
+		BinaryAnnotations: []*zc.BinaryAnnotation{{Key: zc.SERVER_ADDR, Host: &zc.Endpoint{ServiceName: "backend"}}},
+	}
+	span, err := NewServiceNameSanitizer("unknown-service").Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, &zc.Endpoint{ServiceName: "frontend"}, span.Annotations[0].Host)
+	assert.Equal(t, &zc.Endpoint{ServiceName: "backend"}, span.BinaryAnnotations[0].Host)
+	assert.Len(t, span.BinaryAnnotations, 1)
+}
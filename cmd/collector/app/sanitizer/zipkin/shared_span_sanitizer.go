@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const sharedSpanConflictTag = "warnSharedSpanConflict"
+
+// NewSharedSpanConflictSanitizer returns a sanitizer for shared spans carrying both the client (cs/cr)
+// and the server (sr/ss) annotations, where the server interval is not contained in the client
+// interval, e.g. because of clock skew. The span timestamp and duration are derived from the client
+// pair, or from the server pair if preferServer is set. The difference between the client and server
+// durations, in microseconds, is recorded in a 'warnSharedSpanConflict' tag. Annotations are left intact.
+func NewSharedSpanConflictSanitizer(logger *zap.Logger, preferServer bool) Sanitizer {
+	return &sharedSpanConflictSanitizer{log: spanLogger{logger}, preferServer: preferServer}
+}
+
+type sharedSpanConflictSanitizer struct {
+	log          spanLogger
+	preferServer bool
+}
+
+func (s *sharedSpanConflictSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var cs, cr, sr, ss *zc.Annotation
+	for _, anno := range span.Annotations {
+		switch anno.Value {
+		case zc.CLIENT_SEND:
+			cs = anno
+		case zc.CLIENT_RECV:
+			cr = anno
+		case zc.SERVER_RECV:
+			sr = anno
+		case zc.SERVER_SEND:
+			ss = anno
+		}
+	}
+	if cs == nil || cr == nil || sr == nil || ss == nil {
+		return span, nil
+	}
+	if cs.Timestamp <= sr.Timestamp && ss.Timestamp <= cr.Timestamp {
+		return span, nil
+	}
+	start, end := cs.Timestamp, cr.Timestamp
+	if s.preferServer {
+		start, end = sr.Timestamp, ss.Timestamp
+	}
+	duration := end - start
+	span.Timestamp = &start
+	span.Duration = &duration
+	delta := (cr.Timestamp - cs.Timestamp) - (ss.Timestamp - sr.Timestamp)
+	s.log.ForSpan(span).Debug("Conflicting client and server annotations", zap.Int64("delta", delta))
+	appendStringTag(span, sharedSpanConflictTag, strconv.FormatInt(delta, 10))
+	return span, nil
+}
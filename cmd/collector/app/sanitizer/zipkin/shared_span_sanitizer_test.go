@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func sharedSpan(cs, sr, ss, cr int64) *zc.Span {
+	return &zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Timestamp: cs},
+			{Value: zc.SERVER_RECV, Timestamp: sr},
+			{Value: zc.SERVER_SEND, Timestamp: ss},
+			{Value: zc.CLIENT_RECV, Timestamp: cr},
+		},
+	}
+}
+
+func TestSharedSpanConflictSanitizer(t *testing.T) {
+	tests := []struct {
+		preferServer bool
+		timestamp    int64
+		duration     int64
+	}{
+		{preferServer: false, timestamp: 100, duration: 100},
+		{preferServer: true, timestamp: 150, duration: 120},
+	}
+	for _, test := range tests {
+		sanitizer := NewSharedSpanConflictSanitizer(zap.NewNop(), test.preferServer)
+		span, err := sanitizer.Sanitize(sharedSpan(100, 150, 270, 200))
+		require.NoError(t, err)
+		assert.Equal(t, test.timestamp, *span.Timestamp)
+		assert.Equal(t, test.duration, *span.Duration)
+		assert.Len(t, span.Annotations, 4)
+		assert.Equal(t, []*zc.BinaryAnnotation{stringTag(sharedSpanConflictTag, "-20")}, span.BinaryAnnotations)
+	}
+}
+
+func TestSharedSpanConflictSanitizerNoConflict(t *testing.T) {
+	sanitizer := NewSharedSpanConflictSanitizer(zap.NewNop(), false)
+	span, err := sanitizer.Sanitize(sharedSpan(100, 110, 190, 200))
+	require.NoError(t, err)
+	assert.Nil(t, span.Timestamp)
+	assert.Empty(t, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{Annotations: []*zc.Annotation{{Value: zc.CLIENT_SEND}}})
+	require.NoError(t, err)
+	assert.Empty(t, span.BinaryAnnotations)
+}
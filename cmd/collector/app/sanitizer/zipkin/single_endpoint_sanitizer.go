@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const multipleEndpointsNonSharedTag = "warnMultipleEndpointsNonShared"
+
+// NewSingleEndpointSanitizer returns a sanitizer that checks that the annotations of a non-shared span,
+// i.e. one that does not carry both client and server annotations, all reference the same endpoint.
+// Multiple distinct endpoints usually indicate corrupted data; their number is recorded in a
+// 'warnMultipleEndpointsNonShared' tag. Binary annotations are not considered since address annotations
+// such as 'ca' and 'sa' legitimately reference the remote endpoint.
+func NewSingleEndpointSanitizer(logger *zap.Logger) Sanitizer {
+	return &singleEndpointSanitizer{log: spanLogger{logger}}
+}
+
+type singleEndpointSanitizer struct {
+	log spanLogger
+}
+
+func (s *singleEndpointSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if isSharedSpan(span) {
+		return span, nil
+	}
+	endpoints := make(map[zc.Endpoint]struct{})
+	for _, anno := range span.Annotations {
+		if anno.Host != nil {
+			endpoints[*anno.Host] = struct{}{}
+		}
+	}
+	if len(endpoints) <= 1 {
+		return span, nil
+	}
+	s.log.ForSpan(span).Debug("Multiple endpoints on non-shared span", zap.Int("endpoints", len(endpoints)))
+	appendStringTag(span, multipleEndpointsNonSharedTag, strconv.Itoa(len(endpoints)))
+	return span, nil
+}
+
+// isSharedSpan returns true if the span carries both client (cs/cr) and server (sr/ss) annotations,
+// i.e. it was reported by both sides of an RPC.
+func isSharedSpan(span *zc.Span) bool {
+	client, server := false, false
+	for _, anno := range span.Annotations {
+		switch anno.Value {
+		case zc.CLIENT_SEND, zc.CLIENT_RECV:
+			client = true
+		case zc.SERVER_RECV, zc.SERVER_SEND:
+			server = true
+		}
+	}
+	return client && server
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestSingleEndpointSanitizer(t *testing.T) {
+	client := &zc.Endpoint{ServiceName: "frontend", Ipv4: 1}
+	server := &zc.Endpoint{ServiceName: "backend", Ipv4: 2}
+	sanitizer := NewSingleEndpointSanitizer(zap.NewNop())
+
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Host: client},
+			{Value: "retry", Host: server},
+			{Value: zc.CLIENT_RECV, Host: &zc.Endpoint{ServiceName: "frontend", Ipv4: 1}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(multipleEndpointsNonSharedTag, "2")}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Host: client},
+			{Value: zc.SERVER_RECV, Host: server},
+			{Value: zc.SERVER_SEND, Host: server},
+			{Value: zc.CLIENT_RECV, Host: client},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.SERVER_RECV, Host: server},
+			{Value: zc.SERVER_SEND, Host: server},
+		},
+		BinaryAnnotations: []*zc.BinaryAnnotation{{Key: zc.CLIENT_ADDR, Host: client}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 1)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const spanKindKey = "span.kind"
+
+// NewStrictSpanKindFilter returns a batch sanitizer that drops spans whose 'span.kind' tag is not in the
+// allowed set. Both the tag and the allowed kinds are lowercased and trimmed before they are compared.
+// Spans without a 'span.kind' tag are kept.
+func NewStrictSpanKindFilter(allowed map[string]bool, logger *zap.Logger, metricsFactory metrics.Factory) BatchSanitizer {
+	normalized := make(map[string]bool, len(allowed))
+	for kind, ok := range allowed {
+		if ok {
+			normalized[normalizeSpanKind(kind)] = true
+		}
+	}
+	return &strictSpanKindFilter{
+		allowed: normalized,
+		log:     spanLogger{logger},
+		dropped: metricsFactory.Counter("spans.dropped", map[string]string{"reason": "unknown-span-kind"}),
+	}
+}
+
+type strictSpanKindFilter struct {
+	allowed map[string]bool
+	log     spanLogger
+	dropped metrics.Counter
+}
+
+func (f *strictSpanKindFilter) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	return filterSpans(spans, f.keep)
+}
+
+func (f *strictSpanKindFilter) keep(span *zc.Span) bool {
+	binAnno := findBinaryAnnotation(span, spanKindKey)
+	if binAnno == nil {
+		return true
+	}
+	kind := normalizeSpanKind(string(binAnno.Value))
+	if f.allowed[kind] {
+		return true
+	}
+	f.log.ForSpan(span).Warn("Dropping span with unknown span.kind", zap.String("span.kind", kind))
+	f.dropped.Inc(1)
+	return false
+}
+
+func normalizeSpanKind(kind string) string {
+	return strings.ToLower(strings.TrimSpace(kind))
+}
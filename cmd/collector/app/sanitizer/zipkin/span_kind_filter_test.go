@@ -0,0 +1,60 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-lib/metrics"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestStrictSpanKindFilter(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	metricsFactory := metrics.NewLocalFactory(0)
+	filter := NewStrictSpanKindFilter(map[string]bool{"client": true, "server": true}, logger, metricsFactory)
+
+	allowed := &zc.Span{ID: 1, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(spanKindKey, " Server")}}
+	disallowed := &zc.Span{ID: 2, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(spanKindKey, "sideways")}}
+	untagged := &zc.Span{ID: 3}
+	spans := []*zc.Span{allowed, disallowed, untagged}
+
+	actual := filter.SanitizeBatch(spans)
+	assert.Equal(t, []*zc.Span{allowed, untagged}, actual)
+	assert.Equal(t, []*zc.Span{allowed, disallowed, untagged}, spans, "input batch must not be modified")
+
+	counters, _ := metricsFactory.Snapshot()
+	assert.EqualValues(t, 1, counters["spans.dropped|reason=unknown-span-kind"])
+	assert.Equal(t, "sideways", log.JSONLine(0)["span.kind"])
+}
+
+func TestStrictSpanKindFilterMixedCaseAllowed(t *testing.T) {
+	logger, _ := testutils.NewLogger()
+	filter := NewStrictSpanKindFilter(map[string]bool{"SERVER": true, " Client ": true, "producer": false}, logger, metrics.NullFactory)
+
+	server := &zc.Span{ID: 1, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(spanKindKey, "server")}}
+	client := &zc.Span{ID: 2, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(spanKindKey, "CLIENT")}}
+	producer := &zc.Span{ID: 3, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(spanKindKey, "producer")}}
+	assert.Equal(t, []*zc.Span{server, client}, filter.SanitizeBatch([]*zc.Span{server, client, producer}))
+}
@@ -21,6 +21,9 @@
 package zipkin
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"strconv"
 	"strings"
 
@@ -30,35 +33,95 @@ import (
 )
 
 const (
-	negativeDurationTag = "errNegativeDuration"
-	zeroParentIDTag     = "errZeroParentID"
+	negativeDurationTag             = "errNegativeDuration"
+	zeroParentIDTag                 = "errZeroParentID"
+	durationExtendedToAnnotationTag = "warnDurationExtendedToAnnotation"
+	excessiveDurationTag            = "errExcessiveDuration"
+	emptySpanNameTag                = "errEmptySpanName"
+	selfParentTag                   = "errSelfParent"
 )
 
 var (
 	defaultDuration = int64(1)
 )
 
+// ErrDropSpan is returned by sanitizers that deliberately drop a span, as opposed to failing to repair it.
+// Like any other error, it stops ChainedSanitizer so that the remaining sanitizers do not run on the span.
+var ErrDropSpan = errors.New("span dropped by sanitizer")
+
 // Sanitizer interface for sanitizing spans. Any business logic that needs to be applied to normalize the contents of a
-// span should implement this interface.
-// TODO - just make this a function
+// span should implement this interface. An error is returned if the span cannot be repaired and should be dropped.
 type Sanitizer interface {
-	Sanitize(span *zc.Span) *zc.Span
+	Sanitize(span *zc.Span) (*zc.Span, error)
+}
+
+// SanitizerFunc is an adapter to allow the use of ordinary functions as sanitizers.
+type SanitizerFunc func(span *zc.Span) (*zc.Span, error)
+
+// Sanitize calls f(span)
+func (f SanitizerFunc) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return f(span)
+}
+
+// ContextSanitizer is an optional interface of sanitizers that make use of a request-scoped context,
+// e.g. to honor its deadline. ChainedSanitizer calls SanitizeCtx instead of Sanitize when it is implemented.
+type ContextSanitizer interface {
+	SanitizeCtx(ctx context.Context, span *zc.Span) (*zc.Span, error)
+}
+
+// sanitizeCtx calls SanitizeCtx if the sanitizer implements ContextSanitizer, and Sanitize otherwise.
+func sanitizeCtx(ctx context.Context, s Sanitizer, span *zc.Span) (*zc.Span, error) {
+	if cs, ok := s.(ContextSanitizer); ok {
+		return cs.SanitizeCtx(ctx, span)
+	}
+	return s.Sanitize(span)
 }
 
 // ChainedSanitizer applies multiple sanitizers in serial fashion
 type ChainedSanitizer []Sanitizer
 
-// NewChainedSanitizer creates a Sanitizer from the variadic list of passed Sanitizers
+// NewChainedSanitizer creates a Sanitizer from the variadic list of passed Sanitizers, skipping no-op sanitizers
 func NewChainedSanitizer(sanitizers ...Sanitizer) ChainedSanitizer {
-	return sanitizers
+	chain := make(ChainedSanitizer, 0, len(sanitizers))
+	for _, s := range sanitizers {
+		if _, ok := s.(noopSanitizer); !ok {
+			chain = append(chain, s)
+		}
+	}
+	return chain
 }
 
-// Sanitize calls each Sanitize, returning the first error
-func (cs ChainedSanitizer) Sanitize(span *zc.Span) *zc.Span {
+// Sanitize calls each Sanitize, returning the first error, e.g. ErrDropSpan, without calling the rest
+func (cs ChainedSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return cs.SanitizeCtx(context.Background(), span)
+}
+
+// SanitizeCtx calls each SanitizeCtx, or Sanitize for sanitizers that do not implement ContextSanitizer,
+// returning the first error. The remaining sanitizers are skipped once the context is done, and its error
+// is returned.
+func (cs ChainedSanitizer) SanitizeCtx(ctx context.Context, span *zc.Span) (*zc.Span, error) {
 	for _, s := range cs {
-		span = s.Sanitize(span)
+		if err := ctx.Err(); err != nil {
+			return span, err
+		}
+		var err error
+		if span, err = sanitizeCtx(ctx, s, span); err != nil {
+			return span, err
+		}
 	}
-	return span
+	return span, nil
+}
+
+// NewNoopSanitizer returns a sanitizer that returns the span unchanged. It can stand in for a disabled
+// stage of a chain, and is skipped by NewChainedSanitizer.
+func NewNoopSanitizer() Sanitizer {
+	return noopSanitizer{}
+}
+
+type noopSanitizer struct{}
+
+func (noopSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	return span, nil
 }
 
 type spanLogger struct {
@@ -71,7 +134,9 @@ func (s spanLogger) ForSpan(span *zc.Span) *zap.Logger {
 		With(zap.String("spanID", strconv.FormatUint(uint64(span.ID), 16)))
 }
 
-// NewSpanDurationSanitizer returns a sanitizer that deals with nil or 0 span duration.
+// NewSpanDurationSanitizer returns a sanitizer that deals with nil or negative span duration.
+// Such durations are replaced with a default of 1µs, or extended to reach the last annotation
+// if the span has a timestamp and an annotation later than the default end time.
 func NewSpanDurationSanitizer(logger *zap.Logger) Sanitizer {
 	return &spanDurationSanitizer{log: spanLogger{logger}}
 }
@@ -80,23 +145,79 @@ type spanDurationSanitizer struct {
 	log spanLogger
 }
 
-func (s *spanDurationSanitizer) Sanitize(span *zc.Span) *zc.Span {
+func (s *spanDurationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
 	if span.Duration == nil {
-		span.Duration = &defaultDuration
-		return span
+		s.setDefaultDuration(span)
+		return span, nil
 	}
 	duration := *span.Duration
 	if duration >= 0 {
-		return span
+		return span, nil
 	}
+	appendStringTag(span, negativeDurationTag, strconv.FormatInt(duration, 10))
+	s.setDefaultDuration(span)
+	return span, nil
+}
+
+func (s *spanDurationSanitizer) setDefaultDuration(span *zc.Span) {
 	span.Duration = &defaultDuration
-	annotation := zc.BinaryAnnotation{
-		Key:            negativeDurationTag,
-		Value:          []byte(strconv.FormatInt(duration, 10)),
-		AnnotationType: zc.AnnotationType_STRING,
+	if span.Timestamp == nil {
+		return
+	}
+	defaultEnd := *span.Timestamp + defaultDuration
+	end := defaultEnd
+	for _, anno := range span.Annotations {
+		if anno.Timestamp > end {
+			end = anno.Timestamp
+		}
+	}
+	if end == defaultEnd {
+		return
+	}
+	duration := end - *span.Timestamp
+	span.Duration = &duration
+	appendStringTag(span, durationExtendedToAnnotationTag, strconv.FormatInt(duration, 10))
+}
+
+// NewMaxDurationSanitizer returns a sanitizer that clamps span durations greater than max to max.
+// Nil and negative durations are left to the span duration sanitizer.
+func NewMaxDurationSanitizer(logger *zap.Logger, max int64) Sanitizer {
+	return &maxDurationSanitizer{log: spanLogger{logger}, max: max}
+}
+
+type maxDurationSanitizer struct {
+	log spanLogger
+	max int64
+}
+
+func (s *maxDurationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Duration == nil || *span.Duration <= s.max {
+		return span, nil
+	}
+	appendStringTag(span, excessiveDurationTag, strconv.FormatInt(*span.Duration, 10))
+	duration := s.max
+	span.Duration = &duration
+	return span, nil
+}
+
+// NewSpanNameSanitizer returns a sanitizer that trims leading and trailing whitespace from span names,
+// and replaces names that are empty after trimming with defaultName.
+func NewSpanNameSanitizer(defaultName string) Sanitizer {
+	return &spanNameSanitizer{defaultName: defaultName}
+}
+
+type spanNameSanitizer struct {
+	defaultName string
+}
+
+func (s *spanNameSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	name := strings.TrimSpace(span.Name)
+	if name == "" {
+		appendStringTag(span, emptySpanNameTag, span.Name)
+		name = s.defaultName
 	}
-	span.BinaryAnnotations = append(span.BinaryAnnotations, &annotation)
-	return span
+	span.Name = name
+	return span, nil
 }
 
 // NewParentIDSanitizer returns a sanitizer that deals parentID == 0
@@ -109,23 +230,43 @@ type parentIDSanitizer struct {
 	log spanLogger
 }
 
-func (s *parentIDSanitizer) Sanitize(span *zc.Span) *zc.Span {
+func (s *parentIDSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
 	if span.ParentID == nil || *span.ParentID != 0 {
-		return span
+		return span, nil
 	}
-	annotation := zc.BinaryAnnotation{
-		Key:            zeroParentIDTag,
-		Value:          []byte("0"),
-		AnnotationType: zc.AnnotationType_STRING,
+	appendStringTag(span, zeroParentIDTag, "0")
+	span.ParentID = nil
+	return span, nil
+}
+
+var (
+	errorTagTrueValues  = map[string]bool{"true": true, "1": true, "yes": true, "on": true}
+	errorTagFalseValues = map[string]bool{"false": true, "0": true, "no": true, "off": true}
+)
+
+// NewSelfReferenceSanitizer returns a sanitizer that deals with spans whose parentID equals their ID,
+// which would make the span its own parent, by replacing the parentID with nil so that the span becomes a root.
+// It should precede the parentID sanitizer in a chain. A zero parentID is left to the latter.
+func NewSelfReferenceSanitizer(logger *zap.Logger) Sanitizer {
+	return &selfReferenceSanitizer{log: spanLogger{logger}}
+}
+
+type selfReferenceSanitizer struct {
+	log spanLogger
+}
+
+func (s *selfReferenceSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.ParentID == nil || *span.ParentID == 0 || *span.ParentID != span.ID {
+		return span, nil
 	}
-	span.BinaryAnnotations = append(span.BinaryAnnotations, &annotation)
+	appendStringTag(span, selfParentTag, strconv.FormatInt(*span.ParentID, 10))
 	span.ParentID = nil
-	return span
+	return span, nil
 }
 
 // NewErrorTagSanitizer returns a sanitizer that changes error binary annotations to boolean type
 // and sets appropriate value, in case value was a string message it adds a 'error.message' binary annotation with
-// this message.
+// this message. The values true/1/yes/on and false/0/no/off are recognized in any case.
 func NewErrorTagSanitizer() Sanitizer {
 	return &errorTagSanitizer{}
 }
@@ -133,26 +274,39 @@ func NewErrorTagSanitizer() Sanitizer {
 type errorTagSanitizer struct {
 }
 
-func (s *errorTagSanitizer) Sanitize(span *zc.Span) *zc.Span {
+func (s *errorTagSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
 	for _, binAnno := range span.BinaryAnnotations {
 		if binAnno.AnnotationType != zc.AnnotationType_BOOL && strings.EqualFold("error", binAnno.Key) {
 			binAnno.AnnotationType = zc.AnnotationType_BOOL
 
-			if strings.EqualFold("true", string(binAnno.Value)) || len(binAnno.Value) == 0 {
+			value := strings.ToLower(string(binAnno.Value))
+			if errorTagTrueValues[value] || len(binAnno.Value) == 0 {
 				binAnno.Value = []byte{1}
-			} else if strings.EqualFold("false", string(binAnno.Value)) {
+			} else if errorTagFalseValues[value] {
 				binAnno.Value = []byte{0}
 			} else {
-				// value is different to true/false, create another bin annotation with error message
-				annoErrorMsg := &zc.BinaryAnnotation{
-					Key:   "error.message",
-					Value: binAnno.Value,
+				// value is different to true/false, create another bin annotation with error message,
+				// unless the span already has one with the same message
+				if !hasErrorMessage(span, binAnno.Value) {
+					annoErrorMsg := &zc.BinaryAnnotation{
+						Key:   "error.message",
+						Value: binAnno.Value,
+					}
+					span.BinaryAnnotations = append(span.BinaryAnnotations, annoErrorMsg)
 				}
-				span.BinaryAnnotations = append(span.BinaryAnnotations, annoErrorMsg)
 				binAnno.Value = []byte{1}
 			}
 		}
 	}
 
-	return span
+	return span, nil
+}
+
+func hasErrorMessage(span *zc.Span, message []byte) bool {
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Key == "error.message" && bytes.Equal(binAnno.Value, message) {
+			return true
+		}
+	}
+	return false
 }
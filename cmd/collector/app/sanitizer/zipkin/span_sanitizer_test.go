@@ -21,7 +21,10 @@
 package zipkin
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -41,7 +44,116 @@ func TestChainedSanitizer(t *testing.T) {
 	sanitizer := NewChainedSanitizer(NewSpanDurationSanitizer(zap.NewNop()))
 
 	span := &zipkincore.Span{Duration: &negativeDuration}
-	actual := sanitizer.Sanitize(span)
+	actual, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, positiveDuration, *actual.Duration)
+}
+
+func TestNoopSanitizer(t *testing.T) {
+	span := &zipkincore.Span{Duration: &negativeDuration}
+	actual, err := NewNoopSanitizer().Sanitize(span)
+	require.NoError(t, err)
+	assert.True(t, span == actual)
+	assert.Equal(t, negativeDuration, *actual.Duration)
+
+	chain := NewChainedSanitizer(NewNoopSanitizer(), NewSpanDurationSanitizer(zap.NewNop()), NewNoopSanitizer())
+	assert.Len(t, chain, 1)
+}
+
+func ExampleSanitizerFunc() {
+	errDebugSpan := errors.New("debug spans are not accepted")
+	dropDebug := SanitizerFunc(func(span *zipkincore.Span) (*zipkincore.Span, error) {
+		if span.Debug {
+			return span, errDebugSpan
+		}
+		return span, nil
+	})
+	sanitizer := NewChainedSanitizer(dropDebug, NewSpanDurationSanitizer(zap.NewNop()))
+
+	for _, span := range []*zipkincore.Span{{Debug: true}, {Duration: &negativeDuration}} {
+		span, err := sanitizer.Sanitize(span)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(*span.Duration)
+	}
+	// Output: debug spans are not accepted
+	// 1
+}
+
+type failingSanitizer struct{}
+
+var errFailingSanitizer = errors.New("cannot sanitize")
+
+func (s failingSanitizer) Sanitize(span *zipkincore.Span) (*zipkincore.Span, error) {
+	return span, errFailingSanitizer
+}
+
+func TestChainedSanitizerStopsAtFirstError(t *testing.T) {
+	sanitizer := NewChainedSanitizer(failingSanitizer{}, NewSpanDurationSanitizer(zap.NewNop()))
+
+	span := &zipkincore.Span{Duration: &negativeDuration}
+	actual, err := sanitizer.Sanitize(span)
+	assert.Equal(t, errFailingSanitizer, err)
+	assert.Equal(t, negativeDuration, *actual.Duration)
+}
+
+func TestChainedSanitizerDropSpan(t *testing.T) {
+	calls := 0
+	counting := SanitizerFunc(func(span *zipkincore.Span) (*zipkincore.Span, error) {
+		calls++
+		return span, nil
+	})
+	dropping := SanitizerFunc(func(span *zipkincore.Span) (*zipkincore.Span, error) {
+		return span, ErrDropSpan
+	})
+	sanitizer := NewChainedSanitizer(counting, dropping, counting, NewSpanDurationSanitizer(zap.NewNop()))
+
+	span := &zipkincore.Span{Duration: &negativeDuration}
+	actual, err := sanitizer.Sanitize(span)
+	assert.Equal(t, ErrDropSpan, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, negativeDuration, *actual.Duration)
+}
+
+// cancellingSanitizer cancels the context it is given, recording that it was called through SanitizeCtx
+type cancellingSanitizer struct {
+	cancel context.CancelFunc
+	calls  int
+}
+
+func (s *cancellingSanitizer) Sanitize(span *zipkincore.Span) (*zipkincore.Span, error) {
+	return span, nil
+}
+
+func (s *cancellingSanitizer) SanitizeCtx(ctx context.Context, span *zipkincore.Span) (*zipkincore.Span, error) {
+	s.calls++
+	s.cancel()
+	return span, nil
+}
+
+func TestChainedSanitizerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling := &cancellingSanitizer{cancel: cancel}
+	sanitizer := NewChainedSanitizer(NewSpanNameSanitizer("unknown"), cancelling, NewSpanDurationSanitizer(zap.NewNop()))
+
+	span := &zipkincore.Span{Name: " ", Duration: &negativeDuration}
+	actual, err := sanitizer.SanitizeCtx(ctx, span)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, cancelling.calls)
+	assert.Equal(t, "unknown", actual.Name)
+	assert.Equal(t, negativeDuration, *actual.Duration)
+}
+
+func TestChainedSanitizerContextNotCancelled(t *testing.T) {
+	cancelling := &cancellingSanitizer{cancel: func() {}}
+	sanitizer := NewChainedSanitizer(cancelling, NewSpanDurationSanitizer(zap.NewNop()))
+
+	span := &zipkincore.Span{Duration: &negativeDuration}
+	actual, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cancelling.calls)
 	assert.Equal(t, positiveDuration, *actual.Duration)
 }
 
@@ -51,7 +163,8 @@ func TestSpanDurationSanitizer(t *testing.T) {
 	sanitizer := NewSpanDurationSanitizer(logger)
 
 	span := &zipkincore.Span{Duration: &negativeDuration}
-	actual := sanitizer.Sanitize(span)
+	actual, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
 	assert.Equal(t, positiveDuration, *actual.Duration)
 	assert.Len(t, actual.BinaryAnnotations, 1)
 	assert.Equal(t, "-1", string(actual.BinaryAnnotations[0].Value))
@@ -59,17 +172,112 @@ func TestSpanDurationSanitizer(t *testing.T) {
 	logger, _ = testutils.NewLogger()
 	sanitizer = NewSpanDurationSanitizer(logger)
 	span = &zipkincore.Span{Duration: &positiveDuration}
-	actual = sanitizer.Sanitize(span)
+	actual, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
 	assert.Equal(t, positiveDuration, *actual.Duration)
 	assert.Len(t, actual.BinaryAnnotations, 0)
 
 	logger, _ = testutils.NewLogger()
 	sanitizer = NewSpanDurationSanitizer(logger)
 	nilDurationSpan := &zipkincore.Span{}
-	actual = sanitizer.Sanitize(nilDurationSpan)
+	actual, err = sanitizer.Sanitize(nilDurationSpan)
+	require.NoError(t, err)
 	assert.Equal(t, int64(1), *actual.Duration)
 }
 
+func TestSpanDurationSanitizerExtendsToAnnotation(t *testing.T) {
+	sanitizer := NewSpanDurationSanitizer(zap.NewNop())
+	timestamp := int64(1000)
+	annotations := []*zipkincore.Annotation{{Timestamp: 1000}, {Timestamp: 1250}, {Timestamp: 1100}}
+
+	span := &zipkincore.Span{Timestamp: &timestamp, Annotations: annotations}
+	actual, err := sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), *actual.Duration)
+	if assert.Len(t, actual.BinaryAnnotations, 1) {
+		assert.Equal(t, durationExtendedToAnnotationTag, actual.BinaryAnnotations[0].Key)
+		assert.Equal(t, "250", string(actual.BinaryAnnotations[0].Value))
+	}
+
+	span = &zipkincore.Span{Timestamp: &timestamp, Duration: &negativeDuration, Annotations: annotations}
+	actual, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), *actual.Duration)
+	if assert.Len(t, actual.BinaryAnnotations, 2) {
+		assert.Equal(t, negativeDurationTag, actual.BinaryAnnotations[0].Key)
+		assert.Equal(t, durationExtendedToAnnotationTag, actual.BinaryAnnotations[1].Key)
+	}
+
+	span = &zipkincore.Span{Timestamp: &timestamp, Annotations: annotations[:1]}
+	actual, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Equal(t, positiveDuration, *actual.Duration)
+	assert.Len(t, actual.BinaryAnnotations, 0)
+	assert.Equal(t, int64(1), defaultDuration, "the shared default must not be modified")
+}
+
+func TestMaxDurationSanitizer(t *testing.T) {
+	tests := []struct {
+		duration int64
+		expected int64
+		tag      bool
+		descr    string
+	}{
+		{999, 999, false, "below"},
+		{1000, 1000, false, "at"},
+		{1001, 1000, true, "above"},
+		{-1, -1, false, "negative"},
+	}
+	sanitizer := NewMaxDurationSanitizer(zap.NewNop(), 1000)
+	for _, test := range tests {
+		duration := test.duration
+		actual, err := sanitizer.Sanitize(&zipkincore.Span{Duration: &duration})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, *actual.Duration, test.descr)
+		if test.tag {
+			if assert.Len(t, actual.BinaryAnnotations, 1, test.descr) {
+				assert.Equal(t, excessiveDurationTag, actual.BinaryAnnotations[0].Key)
+				assert.Equal(t, "1001", string(actual.BinaryAnnotations[0].Value))
+				assert.Equal(t, zipkincore.AnnotationType_STRING, actual.BinaryAnnotations[0].AnnotationType)
+			}
+		} else {
+			assert.Len(t, actual.BinaryAnnotations, 0, test.descr)
+		}
+	}
+
+	actual, err := sanitizer.Sanitize(&zipkincore.Span{})
+	require.NoError(t, err)
+	assert.Nil(t, actual.Duration)
+}
+
+func TestSpanNameSanitizer(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		tag      bool
+		descr    string
+	}{
+		{"", "unknown", true, "empty"},
+		{" \t ", "unknown", true, "whitespace"},
+		{"GET /foo bar", "GET /foo bar", false, "normal"},
+		{" GET /foo\n", "GET /foo", false, "padded"},
+	}
+	sanitizer := NewSpanNameSanitizer("unknown")
+	for _, test := range tests {
+		actual, err := sanitizer.Sanitize(&zipkincore.Span{Name: test.name})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, actual.Name, test.descr)
+		if test.tag {
+			if assert.Len(t, actual.BinaryAnnotations, 1, test.descr) {
+				assert.Equal(t, emptySpanNameTag, actual.BinaryAnnotations[0].Key)
+				assert.Equal(t, test.name, string(actual.BinaryAnnotations[0].Value))
+			}
+		} else {
+			assert.Len(t, actual.BinaryAnnotations, 0, test.descr)
+		}
+	}
+}
+
 func TestSpanParentIDSanitizer(t *testing.T) {
 	var (
 		zero = int64(0)
@@ -91,7 +299,8 @@ func TestSpanParentIDSanitizer(t *testing.T) {
 		}
 		logger, log := testutils.NewLogger()
 		sanitizer := NewParentIDSanitizer(logger)
-		actual := sanitizer.Sanitize(span)
+		actual, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
 		assert.Equal(t, test.expected, actual.ParentID)
 		if test.tag {
 			if assert.Len(t, actual.BinaryAnnotations, 1) {
@@ -105,6 +314,40 @@ func TestSpanParentIDSanitizer(t *testing.T) {
 	}
 }
 
+func TestSelfReferenceSanitizer(t *testing.T) {
+	var (
+		zero  = int64(0)
+		four  = int64(4)
+		seven = int64(7)
+	)
+	tests := []struct {
+		id       int64
+		parentID *int64
+		expected *int64
+		tag      bool
+		descr    string
+	}{
+		{7, &seven, nil, true, "equal"},
+		{7, &four, &four, false, "unequal"},
+		{7, nil, nil, false, "nil"},
+		{0, &zero, &zero, false, "zero"},
+	}
+	sanitizer := NewSelfReferenceSanitizer(zap.NewNop())
+	for _, test := range tests {
+		actual, err := sanitizer.Sanitize(&zipkincore.Span{ID: test.id, ParentID: test.parentID})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, actual.ParentID, test.descr)
+		if test.tag {
+			if assert.Len(t, actual.BinaryAnnotations, 1, test.descr) {
+				assert.Equal(t, selfParentTag, actual.BinaryAnnotations[0].Key)
+				assert.Equal(t, "7", string(actual.BinaryAnnotations[0].Value))
+			}
+		} else {
+			assert.Len(t, actual.BinaryAnnotations, 0, test.descr)
+		}
+	}
+}
+
 func TestSpanErrorSanitizer(t *testing.T) {
 	sanitizer := NewErrorTagSanitizer()
 
@@ -134,7 +377,8 @@ func TestSpanErrorSanitizer(t *testing.T) {
 			BinaryAnnotations: []*zipkincore.BinaryAnnotation{test.binAnn},
 		}
 
-		sanitized := sanitizer.Sanitize(span)
+		sanitized, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
 		if test.isErrorTag {
 			var expectedVal = []byte{0}
 			if test.isError {
@@ -154,6 +398,57 @@ func TestSpanErrorSanitizer(t *testing.T) {
 	}
 }
 
+func TestSpanErrorSanitizerBooleanVariants(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected byte
+	}{
+		{"true", 1},
+		{"True", 1},
+		{"TRUE", 1},
+		{"1", 1},
+		{"yes", 1},
+		{"Yes", 1},
+		{"on", 1},
+		{"ON", 1},
+		{"false", 0},
+		{"False", 0},
+		{"0", 0},
+		{"no", 0},
+		{"NO", 0},
+		{"off", 0},
+		{"Off", 0},
+	}
+	sanitizer := NewErrorTagSanitizer()
+	for _, test := range tests {
+		span := &zipkincore.Span{
+			BinaryAnnotations: []*zipkincore.BinaryAnnotation{
+				{Key: "error", Value: []byte(test.value), AnnotationType: zipkincore.AnnotationType_STRING},
+			},
+		}
+		sanitized, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		if assert.Len(t, sanitized.BinaryAnnotations, 1, test.value) {
+			assert.Equal(t, zipkincore.AnnotationType_BOOL, sanitized.BinaryAnnotations[0].AnnotationType, test.value)
+			assert.Equal(t, []byte{test.expected}, sanitized.BinaryAnnotations[0].Value, test.value)
+		}
+	}
+}
+
+func TestSpanErrorSanitizerExistingMessage(t *testing.T) {
+	span, err := NewErrorTagSanitizer().Sanitize(&zipkincore.Span{
+		BinaryAnnotations: []*zipkincore.BinaryAnnotation{
+			{Key: "error.message", Value: []byte("message"), AnnotationType: zipkincore.AnnotationType_STRING},
+			{Key: "error", Value: []byte("message"), AnnotationType: zipkincore.AnnotationType_STRING},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zipkincore.BinaryAnnotation{
+		{Key: "error.message", Value: []byte("message"), AnnotationType: zipkincore.AnnotationType_STRING},
+		{Key: "error", Value: []byte{1}, AnnotationType: zipkincore.AnnotationType_BOOL},
+	}, span.BinaryAnnotations)
+}
+
 func TestSpanLogger(t *testing.T) {
 	logger, log := testutils.NewLogger()
 	span := &zipkincore.Span{
@@ -173,3 +468,68 @@ func TestSpanLogger(t *testing.T) {
 		"traceID": "7b",
 	}, data)
 }
+
+// benchmarkSpan returns a span with a zero parentID, a negative duration, the core annotations and 15 binary
+// annotations including a string error tag, so that each of the default sanitizers has work to do, and a
+// function restoring the span to that state without allocating.
+func benchmarkSpan() (*zipkincore.Span, func()) {
+	host := &zipkincore.Endpoint{Ipv4: 1<<24 | 2<<16 | 3<<8 | 4, Port: 8080, ServiceName: "frontend"}
+	var annos []*zipkincore.Annotation
+	for i, value := range []string{zipkincore.CLIENT_SEND, zipkincore.SERVER_RECV, zipkincore.SERVER_SEND, zipkincore.CLIENT_RECV} {
+		annos = append(annos, &zipkincore.Annotation{Timestamp: int64(1500000000000000 + i*100), Value: value, Host: host})
+	}
+	binAnnos := []*zipkincore.BinaryAnnotation{{Key: "error", Host: host}}
+	for i := 1; i < 15; i++ {
+		binAnnos = append(binAnnos, &zipkincore.BinaryAnnotation{
+			Key:            fmt.Sprintf("tag.%d", i),
+			Value:          []byte(fmt.Sprintf("value %d", i)),
+			AnnotationType: zipkincore.AnnotationType_STRING,
+			Host:           host,
+		})
+	}
+	errorValue := []byte("timeout")
+	timestamp := int64(1500000000000000)
+	duration, parentID := negativeDuration, int64(0)
+	span := &zipkincore.Span{TraceID: 42, ID: 43, Name: "get", Timestamp: &timestamp, Annotations: annos}
+	reset := func() {
+		duration = negativeDuration
+		span.Duration = &duration
+		parentID = 0
+		span.ParentID = &parentID
+		binAnnos[0].AnnotationType = zipkincore.AnnotationType_STRING
+		binAnnos[0].Value = errorValue
+		span.BinaryAnnotations = binAnnos[:15]
+	}
+	reset()
+	return span, reset
+}
+
+func benchmarkResetSanitizer(b *testing.B, sanitizer Sanitizer) {
+	span, reset := benchmarkSpan()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reset()
+		sanitizer.Sanitize(span)
+	}
+}
+
+// BenchmarkSpanDurationSanitizer 	 3064249	       394.4 ns/op	     424 B/op	       8 allocs/op
+func BenchmarkSpanDurationSanitizer(b *testing.B) {
+	benchmarkResetSanitizer(b, NewSpanDurationSanitizer(zap.NewNop()))
+}
+
+// BenchmarkParentIDSanitizer     	13616348	        86.07 ns/op	      72 B/op	       2 allocs/op
+func BenchmarkParentIDSanitizer(b *testing.B) {
+	benchmarkResetSanitizer(b, NewParentIDSanitizer(zap.NewNop()))
+}
+
+// BenchmarkErrorTagSanitizer     	 6107439	       188.8 ns/op	      65 B/op	       2 allocs/op
+func BenchmarkErrorTagSanitizer(b *testing.B) {
+	benchmarkResetSanitizer(b, NewErrorTagSanitizer())
+}
+
+// BenchmarkChainedSanitizer      	 1825585	       679.4 ns/op	     560 B/op	      12 allocs/op
+func BenchmarkChainedSanitizer(b *testing.B) {
+	benchmarkResetSanitizer(b, NewDefaultSanitizer(zap.NewNop()))
+}
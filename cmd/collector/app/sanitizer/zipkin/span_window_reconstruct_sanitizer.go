@@ -0,0 +1,58 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const reconstructedSpanWindowTag = "warnReconstructedSpanWindow"
+
+// NewSpanWindowReconstructSanitizer returns a sanitizer that reconstructs the timestamp and duration of spans
+// where both are nil or zero, from the earliest and latest annotation timestamps. With a single annotation, or
+// annotations at the same time, the duration falls back to the default of 1µs. The reconstructed duration is
+// recorded in a 'warnReconstructedSpanWindow' tag.
+func NewSpanWindowReconstructSanitizer() Sanitizer {
+	return &spanWindowReconstructSanitizer{}
+}
+
+type spanWindowReconstructSanitizer struct {
+}
+
+func (s *spanWindowReconstructSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if (span.Timestamp != nil && *span.Timestamp != 0) || (span.Duration != nil && *span.Duration != 0) {
+		return span, nil
+	}
+	earliest, latest := annotationWindow(span)
+	if earliest == 0 {
+		return span, nil
+	}
+	span.Timestamp = &earliest
+	if duration := latest - earliest; duration > 0 {
+		span.Duration = &duration
+	} else {
+		span.Duration = &defaultDuration
+	}
+	appendStringTag(span, reconstructedSpanWindowTag, strconv.FormatInt(*span.Duration, 10))
+	return span, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestSpanWindowReconstructSanitizer(t *testing.T) {
+	sanitizer := NewSpanWindowReconstructSanitizer()
+	zero := int64(0)
+
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Timestamp: &zero,
+		Duration:  &zero,
+		Annotations: []*zc.Annotation{
+			{Value: zc.SERVER_SEND, Timestamp: 250},
+			{Value: zc.SERVER_RECV, Timestamp: 100},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), *span.Timestamp)
+	assert.Equal(t, int64(150), *span.Duration)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(reconstructedSpanWindowTag, "150")}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV, Timestamp: 100}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), *span.Timestamp)
+	assert.Equal(t, defaultDuration, *span.Duration)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(reconstructedSpanWindowTag, "1")}, span.BinaryAnnotations)
+
+	timestamp := int64(50)
+	span, err = sanitizer.Sanitize(&zc.Span{
+		Timestamp:   &timestamp,
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV, Timestamp: 100}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), *span.Timestamp)
+	assert.Nil(t, span.Duration)
+	assert.Empty(t, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{})
+	require.NoError(t, err)
+	assert.Nil(t, span.Timestamp)
+	assert.Empty(t, span.BinaryAnnotations)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"bytes"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	sqlNormalizedTag = "warnSQLNormalized"
+	dbStatementKey   = "db.statement"
+)
+
+// NewSQLNormalizeSanitizer returns a sanitizer that replaces the literal values in the 'db.statement' tag,
+// i.e. numbers and quoted strings, with '?' placeholders, to reduce its cardinality and hide sensitive values.
+// Quoted identifiers and the rest of the statement, including multiple statements, are preserved. If keepRaw
+// is set, the original statement is kept in a 'db.statement.raw' tag. Normalized statements are flagged
+// with a 'warnSQLNormalized' tag.
+func NewSQLNormalizeSanitizer(keepRaw bool) Sanitizer {
+	return &sqlNormalizeSanitizer{keepRaw: keepRaw}
+}
+
+type sqlNormalizeSanitizer struct {
+	keepRaw bool
+}
+
+func (s *sqlNormalizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	binAnno := findBinaryAnnotation(span, dbStatementKey)
+	if binAnno == nil || binAnno.AnnotationType != zc.AnnotationType_STRING {
+		return span, nil
+	}
+	statement := string(binAnno.Value)
+	normalized := normalizeSQL(statement)
+	if normalized == statement {
+		return span, nil
+	}
+	binAnno.Value = []byte(normalized)
+	if s.keepRaw {
+		appendStringTag(span, dbStatementKey+rawValueSuffix, statement)
+	}
+	appendStringTag(span, sqlNormalizedTag, dbStatementKey)
+	return span, nil
+}
+
+// normalizeSQL replaces the numeric and quoted string literals of the SQL statement with placeholders
+// in a single pass over the statement.
+func normalizeSQL(statement string) string {
+	var buf bytes.Buffer
+	buf.Grow(len(statement))
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'':
+			i = skipSQLString(statement, i+1)
+			buf.WriteByte('?')
+		case c == '"' || c == '`':
+			end := strings.IndexByte(statement[i+1:], c)
+			if end < 0 {
+				end = len(statement)
+			} else {
+				end += i + 2
+			}
+			buf.WriteString(statement[i:end])
+			i = end
+		case isSQLIdentByte(c):
+			end := i + 1
+			for end < len(statement) && (isSQLIdentByte(statement[end]) || statement[end] == '.') {
+				end++
+			}
+			if c >= '0' && c <= '9' {
+				buf.WriteByte('?')
+			} else {
+				buf.WriteString(statement[i:end])
+			}
+			i = end
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return buf.String()
+}
+
+// skipSQLString returns the index following the end of the string literal starting at i, honoring both
+// doubled quotes and backslash escapes.
+func skipSQLString(statement string, i int) int {
+	for i < len(statement) {
+		switch statement[i] {
+		case '\\':
+			i += 2
+		case '\'':
+			if i+1 < len(statement) && statement[i+1] == '\'' {
+				i += 2
+				continue
+			}
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(statement)
+}
+
+func isSQLIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
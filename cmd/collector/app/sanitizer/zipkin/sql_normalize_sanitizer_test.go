@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestSQLNormalizeSanitizer(t *testing.T) {
+	statement := "SELECT * FROM orders WHERE id = 42 AND status = 'it''s paid'"
+	span, err := NewSQLNormalizeSanitizer(true).Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag(dbStatementKey, statement)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag(dbStatementKey, "SELECT * FROM orders WHERE id = ? AND status = ?"),
+		stringTag("db.statement.raw", statement),
+		stringTag(sqlNormalizedTag, dbStatementKey),
+	}, span.BinaryAnnotations)
+
+	span, err = NewSQLNormalizeSanitizer(false).Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 3)
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := []struct {
+		statement string
+		expected  string
+	}{
+		{
+			statement: "UPDATE t1 SET price = 3.5, code = 0x1F WHERE name = 'a\\'b'; DELETE FROM t2 WHERE x IN (1, -2)",
+			expected:  "UPDATE t1 SET price = ?, code = ? WHERE name = ?; DELETE FROM t2 WHERE x IN (?, -?)",
+		},
+		{
+			statement: `SELECT "col 1", ` + "`tbl2`.c3" + ` FROM s.tbl2 WHERE v > 10`,
+			expected:  `SELECT "col 1", ` + "`tbl2`.c3" + ` FROM s.tbl2 WHERE v > ?`,
+		},
+		{
+			statement: "SELECT 'unterminated",
+			expected:  "SELECT ?",
+		},
+		{
+			statement: "SELECT * FROM t WHERE a = ? AND b = $1",
+			expected:  "SELECT * FROM t WHERE a = ? AND b = $1",
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, normalizeSQL(test.statement), test.statement)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const normalizedStackTraceTag = "warnNormalizedStackTrace"
+
+// stackTraceLineSeparators replaces the CRLF and CR line separators with LF.
+var stackTraceLineSeparators = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// escapedStackTraceLineSeparators unescapes the literal '\r\n' and '\n' line separators.
+var escapedStackTraceLineSeparators = strings.NewReplacer(`\r\n`, "\n", `\n`, "\n")
+
+// NewStackTraceNormalizeSanitizer returns a sanitizer that normalizes the line separators of the STRING tags
+// with the given keys, e.g. 'error.stack' or 'exception.stacktrace', to '\n'. A stack trace without any line
+// break but with literal '\n' sequences was escaped as a whole by its runtime, and is unescaped. The frames are
+// otherwise preserved. The keys of the normalized tags are recorded in a 'warnNormalizedStackTrace' tag.
+func NewStackTraceNormalizeSanitizer(keys []string) Sanitizer {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[key] = struct{}{}
+	}
+	return &stackTraceNormalizeSanitizer{keys: keySet}
+}
+
+type stackTraceNormalizeSanitizer struct {
+	keys map[string]struct{}
+}
+
+func (s *stackTraceNormalizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var normalized []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if _, ok := s.keys[binAnno.Key]; !ok || binAnno.AnnotationType != zc.AnnotationType_STRING {
+			continue
+		}
+		value := string(binAnno.Value)
+		stack := stackTraceLineSeparators.Replace(value)
+		if !strings.Contains(stack, "\n") {
+			stack = escapedStackTraceLineSeparators.Replace(stack)
+		}
+		if stack != value {
+			binAnno.Value = []byte(stack)
+			normalized = append(normalized, binAnno.Key)
+		}
+	}
+	if len(normalized) > 0 {
+		appendStringTag(span, normalizedStackTraceTag, strings.Join(normalized, ","))
+	}
+	return span, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestStackTraceNormalizeSanitizer(t *testing.T) {
+	tests := []struct {
+		input      string
+		expected   string
+		normalized bool
+	}{
+		{
+			input:      "System.Exception: boom\r\n   at Foo.Bar()\r\n   at Foo.Main()",
+			expected:   "System.Exception: boom\n   at Foo.Bar()\n   at Foo.Main()",
+			normalized: true,
+		},
+		{
+			input:      `java.lang.IllegalStateException: boom\n\tat com.foo.Bar.baz(Bar.java:42)\r\n\tat com.foo.Main.main(Main.java:7)`,
+			expected:   "java.lang.IllegalStateException: boom\n\\tat com.foo.Bar.baz(Bar.java:42)\n\\tat com.foo.Main.main(Main.java:7)",
+			normalized: true,
+		},
+		{
+			input:    "panic: boom\n\tmain.go:12 path\\name",
+			expected: "panic: boom\n\tmain.go:12 path\\name",
+		},
+		{
+			input:    "panic: boom\n\tmatch \\n literally",
+			expected: "panic: boom\n\tmatch \\n literally",
+		},
+	}
+	sanitizer := NewStackTraceNormalizeSanitizer([]string{"error.stack", "exception.stacktrace"})
+	for _, test := range tests {
+		span, err := sanitizer.Sanitize(&zc.Span{
+			BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("error.stack", test.input)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, string(span.BinaryAnnotations[0].Value))
+		if test.normalized {
+			assert.Equal(t, stringTag(normalizedStackTraceTag, "error.stack"), span.BinaryAnnotations[1])
+		} else {
+			assert.Len(t, span.BinaryAnnotations, 1)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const suspiciousDurationTag = "warnSuspiciousDuration"
+
+// NewSuspiciousDurationSanitizer returns a sanitizer that tags spans whose duration is exactly one of
+// the given round values, e.g. 1000000µs, which usually indicates a placeholder rather than a measurement.
+// The duration is recorded in a 'warnSuspiciousDuration' tag; the span is otherwise left untouched.
+func NewSuspiciousDurationSanitizer(roundValues []int64) Sanitizer {
+	values := make(map[int64]struct{}, len(roundValues))
+	for _, value := range roundValues {
+		values[value] = struct{}{}
+	}
+	return &suspiciousDurationSanitizer{values: values}
+}
+
+type suspiciousDurationSanitizer struct {
+	values map[int64]struct{}
+}
+
+func (s *suspiciousDurationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Duration == nil {
+		return span, nil
+	}
+	if _, ok := s.values[*span.Duration]; ok && findBinaryAnnotation(span, suspiciousDurationTag) == nil {
+		appendStringTag(span, suspiciousDurationTag, strconv.FormatInt(*span.Duration, 10))
+	}
+	return span, nil
+}
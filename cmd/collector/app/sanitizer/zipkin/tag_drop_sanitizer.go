@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewTagDropSanitizer returns a sanitizer that removes the binary annotations whose key matches any of the
+// patterns, e.g. high-cardinality tags like 'request.id'. Patterns are not anchored unless they use ^ and $.
+// The number of binary annotations removed by each pattern is logged. The sanitizer holds no mutable state,
+// so it can be used concurrently.
+func NewTagDropSanitizer(patterns []*regexp.Regexp, logger *zap.Logger) Sanitizer {
+	return &tagDropSanitizer{patterns: patterns, log: spanLogger{logger}}
+}
+
+type tagDropSanitizer struct {
+	patterns []*regexp.Regexp
+	log      spanLogger
+}
+
+func (s *tagDropSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var dropped []int
+	binAnnos := span.BinaryAnnotations[:0]
+	for _, binAnno := range span.BinaryAnnotations {
+		if i := s.match(binAnno.Key); i >= 0 {
+			if dropped == nil {
+				dropped = make([]int, len(s.patterns))
+			}
+			dropped[i]++
+			continue
+		}
+		binAnnos = append(binAnnos, binAnno)
+	}
+	span.BinaryAnnotations = binAnnos
+	if dropped == nil {
+		return span, nil
+	}
+	for i, pattern := range s.patterns {
+		if dropped[i] > 0 {
+			s.log.ForSpan(span).Debug("Dropped tags", zap.String("pattern", pattern.String()), zap.Int("dropped", dropped[i]))
+		}
+	}
+	return span, nil
+}
+
+// match returns the index of the first pattern matching the key, or -1.
+func (s *tagDropSanitizer) match(key string) int {
+	for i, pattern := range s.patterns {
+		if pattern.MatchString(key) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTagDropSanitizer(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	sanitizer := NewTagDropSanitizer([]*regexp.Regexp{
+		regexp.MustCompile(`^request\.id$`),
+		regexp.MustCompile(`uuid`),
+	}, logger)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("request.id", "1"),
+			stringTag("http.method", "GET"),
+			stringTag("upstream.request.id", "2"),
+			stringTag("session.uuid", "3"),
+			stringTag("uuid", "4"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("http.method", "GET"),
+		stringTag("upstream.request.id", "2"),
+	}, span.BinaryAnnotations)
+	if assert.Len(t, log.Lines(), 2) {
+		assert.Contains(t, log.Lines()[0], `"pattern":"^request\\.id$","dropped":1`)
+		assert.Contains(t, log.Lines()[1], `"pattern":"uuid","dropped":2`)
+	}
+}
+
+func TestTagDropSanitizerNoMatch(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	sanitizer := NewTagDropSanitizer([]*regexp.Regexp{regexp.MustCompile(`^request\.id$`)}, logger)
+	binAnnos := []*zc.BinaryAnnotation{stringTag("http.method", "GET")}
+	span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: binAnnos})
+	require.NoError(t, err)
+	assert.Equal(t, binAnnos, span.BinaryAnnotations)
+	assert.Empty(t, log.Lines())
+}
+
+// BenchmarkTagDropSanitizer 	  415084	      3421 ns/op	    1040 B/op	      11 allocs/op
+func BenchmarkTagDropSanitizer(b *testing.B) {
+	binAnnos := make([]*zc.BinaryAnnotation, 40)
+	for i := range binAnnos {
+		binAnnos[i] = stringTag("key-"+strconv.Itoa(i), "value")
+	}
+	binAnnos[10].Key, binAnnos[30].Key = "request.id", "session.uuid"
+	sanitizer := NewTagDropSanitizer([]*regexp.Regexp{
+		regexp.MustCompile(`^request\.id$`),
+		regexp.MustCompile(`uuid`),
+	}, zap.NewNop())
+	span := &zc.Span{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		span.BinaryAnnotations = append(span.BinaryAnnotations[:0], binAnnos...)
+		sanitizer.Sanitize(span)
+	}
+}
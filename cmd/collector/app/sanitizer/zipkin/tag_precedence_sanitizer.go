@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// NewTagPrecedenceSanitizer returns a sanitizer that keeps a single binary annotation for each of the keys
+// of rules, e.g. 'component' when a library is instrumented twice. The last occurrence is kept if the rule
+// is true, and the first one otherwise. Other binary annotations are left untouched and in order.
+func NewTagPrecedenceSanitizer(rules map[string]bool, logger *zap.Logger) Sanitizer {
+	return &tagPrecedenceSanitizer{rules: rules, log: spanLogger{logger}}
+}
+
+type tagPrecedenceSanitizer struct {
+	rules map[string]bool
+	log   spanLogger
+}
+
+func (s *tagPrecedenceSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	kept := make(map[string]int)
+	for i, binAnno := range span.BinaryAnnotations {
+		keepLast, ok := s.rules[binAnno.Key]
+		if !ok {
+			continue
+		}
+		if _, seen := kept[binAnno.Key]; !seen || keepLast {
+			kept[binAnno.Key] = i
+		}
+	}
+	if len(kept) == 0 {
+		return span, nil
+	}
+	binAnnos := span.BinaryAnnotations[:0]
+	for i, binAnno := range span.BinaryAnnotations {
+		if j, ok := kept[binAnno.Key]; ok && i != j {
+			s.log.ForSpan(span).Debug("Removed duplicate tag",
+				zap.String("key", binAnno.Key), zap.String("value", string(binAnno.Value)))
+			continue
+		}
+		binAnnos = append(binAnnos, binAnno)
+	}
+	span.BinaryAnnotations = binAnnos
+	return span, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTagPrecedenceSanitizer(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	sanitizer := NewTagPrecedenceSanitizer(map[string]bool{"component": true, "peer.service": false}, logger)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("component", "net/http"),
+			stringTag("peer.service", "db"),
+			stringTag("http.method", "GET"),
+			stringTag("component", "grpc"),
+			stringTag("peer.service", "cache"),
+			stringTag("http.method", "POST"),
+			stringTag("component", "thrift"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("peer.service", "db"),
+		stringTag("http.method", "GET"),
+		stringTag("http.method", "POST"),
+		stringTag("component", "thrift"),
+	}, span.BinaryAnnotations)
+	if assert.Len(t, log.Lines(), 3) {
+		assert.Equal(t, "net/http", log.JSONLine(0)["value"])
+		assert.Equal(t, "grpc", log.JSONLine(1)["value"])
+		assert.Equal(t, "cache", log.JSONLine(2)["value"])
+	}
+}
+
+func TestTagPrecedenceSanitizerUnlisted(t *testing.T) {
+	logger, log := testutils.NewLogger()
+	sanitizer := NewTagPrecedenceSanitizer(map[string]bool{"component": true}, logger)
+	binAnnos := []*zc.BinaryAnnotation{stringTag("http.method", "GET"), stringTag("http.method", "POST")}
+	span, err := sanitizer.Sanitize(&zc.Span{BinaryAnnotations: binAnnos})
+	require.NoError(t, err)
+	assert.Equal(t, binAnnos, span.BinaryAnnotations)
+	assert.Empty(t, log.Lines())
+}
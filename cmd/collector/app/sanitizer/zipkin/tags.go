@@ -0,0 +1,327 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const ellipsis = "\u2026"
+
+// appendStringTag appends a binary annotation of AnnotationType_STRING to the span.
+// Sanitizers use it to record what they changed.
+func appendStringTag(span *zc.Span, key, value string) {
+	annotation := zc.BinaryAnnotation{
+		Key:            key,
+		Value:          []byte(value),
+		AnnotationType: zc.AnnotationType_STRING,
+	}
+	span.BinaryAnnotations = append(span.BinaryAnnotations, &annotation)
+}
+
+// appendInt64Tag appends a binary annotation of AnnotationType_I64 to the span.
+func appendInt64Tag(span *zc.Span, key string, value int64) {
+	annotation := zc.BinaryAnnotation{
+		Key:            key,
+		Value:          int64Bytes(value),
+		AnnotationType: zc.AnnotationType_I64,
+	}
+	span.BinaryAnnotations = append(span.BinaryAnnotations, &annotation)
+}
+
+// setStringTag sets the value of the first binary annotation with the given key to a STRING value,
+// appending a new tag if there is none, and returns true if the span was changed.
+func setStringTag(span *zc.Span, key, value string) bool {
+	binAnno := findBinaryAnnotation(span, key)
+	if binAnno == nil {
+		appendStringTag(span, key, value)
+		return true
+	}
+	if binAnno.AnnotationType == zc.AnnotationType_STRING && string(binAnno.Value) == value {
+		return false
+	}
+	binAnno.AnnotationType = zc.AnnotationType_STRING
+	binAnno.Value = []byte(value)
+	return true
+}
+
+// removeBinaryAnnotations removes all binary annotations with the given key and returns how many were removed.
+func removeBinaryAnnotations(span *zc.Span, key string) int {
+	binAnnos := span.BinaryAnnotations[:0]
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Key != key {
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	removed := len(span.BinaryAnnotations) - len(binAnnos)
+	span.BinaryAnnotations = binAnnos
+	return removed
+}
+
+// int64Bytes encodes the value as the big-endian 8 bytes expected in I64 binary annotations.
+func int64Bytes(value int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(value))
+	return b
+}
+
+// int32Bytes encodes the value as the big-endian 4 bytes expected in I32 binary annotations.
+func int32Bytes(value int32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(value))
+	return b
+}
+
+// int16Bytes encodes the value as the big-endian 2 bytes expected in I16 binary annotations.
+func int16Bytes(value int16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, uint16(value))
+	return b
+}
+
+// float64Bytes encodes the value as the big-endian 8 bytes expected in DOUBLE binary annotations.
+func float64Bytes(value float64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(value))
+	return b
+}
+
+// findBinaryAnnotation returns the first binary annotation with the given key, or nil.
+func findBinaryAnnotation(span *zc.Span, key string) *zc.BinaryAnnotation {
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Key == key {
+			return binAnno
+		}
+	}
+	return nil
+}
+
+// hasErrorTag returns true if the span has an 'error' binary annotation, matching the key case-insensitively
+// like the error tag sanitizer does.
+func hasErrorTag(span *zc.Span) bool {
+	for _, binAnno := range span.BinaryAnnotations {
+		if strings.EqualFold(errorKey, binAnno.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// findServiceName returns the first non-empty service name found on the span's endpoints,
+// looking at annotations before binary annotations.
+func findServiceName(span *zc.Span) string {
+	for _, anno := range span.Annotations {
+		if anno.Host != nil && anno.Host.ServiceName != "" {
+			return anno.Host.ServiceName
+		}
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.Host != nil && binAnno.Host.ServiceName != "" {
+			return binAnno.Host.ServiceName
+		}
+	}
+	return ""
+}
+
+// decodeInt decodes the big-endian value of an I16, I32 or I64 binary annotation.
+func decodeInt(binAnno *zc.BinaryAnnotation) (int64, error) {
+	switch binAnno.AnnotationType {
+	case zc.AnnotationType_I16:
+		if len(binAnno.Value) == 2 {
+			return int64(int16(binary.BigEndian.Uint16(binAnno.Value))), nil
+		}
+	case zc.AnnotationType_I32:
+		if len(binAnno.Value) == 4 {
+			return int64(int32(binary.BigEndian.Uint32(binAnno.Value))), nil
+		}
+	case zc.AnnotationType_I64:
+		if len(binAnno.Value) == 8 {
+			return int64(binary.BigEndian.Uint64(binAnno.Value)), nil
+		}
+	default:
+		return 0, fmt.Errorf("not an integer annotation type: %v", binAnno.AnnotationType)
+	}
+	return 0, fmt.Errorf("invalid %v value length: %d", binAnno.AnnotationType, len(binAnno.Value))
+}
+
+// decodeDouble decodes the big-endian value of a DOUBLE binary annotation.
+func decodeDouble(binAnno *zc.BinaryAnnotation) (float64, error) {
+	if binAnno.AnnotationType != zc.AnnotationType_DOUBLE {
+		return 0, fmt.Errorf("not a double annotation type: %v", binAnno.AnnotationType)
+	}
+	if len(binAnno.Value) != 8 {
+		return 0, fmt.Errorf("invalid DOUBLE value length: %d", len(binAnno.Value))
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(binAnno.Value)), nil
+}
+
+// valueString returns the textual representation of the value of a binary annotation,
+// or false if the value cannot be decoded.
+func valueString(binAnno *zc.BinaryAnnotation) (string, bool) {
+	switch binAnno.AnnotationType {
+	case zc.AnnotationType_STRING:
+		return string(binAnno.Value), true
+	case zc.AnnotationType_BOOL:
+		if len(binAnno.Value) != 1 {
+			return "", false
+		}
+		return strconv.FormatBool(binAnno.Value[0] == 1), true
+	case zc.AnnotationType_I16, zc.AnnotationType_I32, zc.AnnotationType_I64:
+		i, err := decodeInt(binAnno)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatInt(i, 10), true
+	case zc.AnnotationType_DOUBLE:
+		d, err := decodeDouble(binAnno)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(d, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// truncateRunes truncates the value to at most maxLen runes, the last of which is an ellipsis,
+// and returns true if the value was truncated.
+func truncateRunes(value string, maxLen int) (string, bool) {
+	if utf8.RuneCountInString(value) <= maxLen {
+		return value, false
+	}
+	if maxLen < 1 {
+		return "", true
+	}
+	kept := 0
+	for i := range value {
+		if kept == maxLen-1 {
+			return value[:i] + ellipsis, true
+		}
+		kept++
+	}
+	return value + ellipsis, true
+}
+
+// truncateUTF8 truncates the value to at most maxBytes bytes without splitting a UTF-8 encoded character.
+func truncateUTF8(value string, maxBytes int) string {
+	if len(value) <= maxBytes {
+		return value
+	}
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end]
+}
+
+// annotationWindow returns the earliest and latest non-zero annotation timestamps, or zeros if there are none.
+func annotationWindow(span *zc.Span) (earliest, latest int64) {
+	for _, anno := range span.Annotations {
+		if anno.Timestamp == 0 {
+			continue
+		}
+		if earliest == 0 || anno.Timestamp < earliest {
+			earliest = anno.Timestamp
+		}
+		if anno.Timestamp > latest {
+			latest = anno.Timestamp
+		}
+	}
+	return earliest, latest
+}
+
+// isCoreAnnotation returns true for the cs, cr, sr and ss annotations.
+func isCoreAnnotation(anno *zc.Annotation) bool {
+	switch anno.Value {
+	case zc.CLIENT_SEND, zc.CLIENT_RECV, zc.SERVER_RECV, zc.SERVER_SEND:
+		return true
+	}
+	return false
+}
+
+// mergeStringTags merges the string tags with any of the given keys into a single tag with the canonical
+// key keys[0]. choose picks the tag whose value is kept among the candidates, which are passed in span order.
+// The keys of the merged tags are returned, or nil if the span was left untouched because there was nothing
+// to choose from or the only candidate already has the canonical key.
+func mergeStringTags(span *zc.Span, keys []string, choose func(candidates []*zc.BinaryAnnotation) *zc.BinaryAnnotation) []string {
+	isCandidate := func(binAnno *zc.BinaryAnnotation) bool {
+		if binAnno.AnnotationType != zc.AnnotationType_STRING {
+			return false
+		}
+		for _, key := range keys {
+			if binAnno.Key == key {
+				return true
+			}
+		}
+		return false
+	}
+	var candidates []*zc.BinaryAnnotation
+	for _, binAnno := range span.BinaryAnnotations {
+		if isCandidate(binAnno) {
+			candidates = append(candidates, binAnno)
+		}
+	}
+	chosen := choose(candidates)
+	if chosen == nil || (len(candidates) == 1 && chosen.Key == keys[0]) {
+		return nil
+	}
+	merged := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		merged[i] = candidate.Key
+	}
+	binAnnos := make([]*zc.BinaryAnnotation, 0, len(span.BinaryAnnotations)-len(candidates)+1)
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno == chosen || !isCandidate(binAnno) {
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	chosen.Key = keys[0]
+	span.BinaryAnnotations = binAnnos
+	return merged
+}
+
+// keyRank returns the position of the annotation's key in keys, or len(keys) if it is not there.
+func keyRank(binAnno *zc.BinaryAnnotation, keys []string) int {
+	for i, key := range keys {
+		if binAnno.Key == key {
+			return i
+		}
+	}
+	return len(keys)
+}
+
+// isSanitizerTag returns true for the keys of the tags added by sanitizers, e.g. 'errNegativeDuration'
+// or 'warnReprocessed'.
+func isSanitizerTag(key string) bool {
+	for _, prefix := range []string{"err", "warn"} {
+		if len(key) > len(prefix) && strings.HasPrefix(key, prefix) && unicode.IsUpper(rune(key[len(prefix)])) {
+			return true
+		}
+	}
+	return key == sanitizedStampTag
+}
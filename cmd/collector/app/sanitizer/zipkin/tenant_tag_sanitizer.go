@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	tenantInferredTag = "warnTenantInferred"
+	tenantIDKey       = "tenant.id"
+)
+
+// NewTenantTagSanitizer returns a sanitizer that adds a 'tenant.id' tag to spans without one, using the
+// resolver to infer the tenant from any signal on the span, e.g. the subnet of an endpoint or a header tag.
+// Spans for which the resolver returns false are left untouched. The inferred tenant is also recorded
+// in a 'warnTenantInferred' tag.
+func NewTenantTagSanitizer(resolver func(*zc.Span) (string, bool)) Sanitizer {
+	return &tenantTagSanitizer{resolver: resolver}
+}
+
+type tenantTagSanitizer struct {
+	resolver func(*zc.Span) (string, bool)
+}
+
+func (s *tenantTagSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if findBinaryAnnotation(span, tenantIDKey) != nil {
+		return span, nil
+	}
+	tenant, ok := s.resolver(span)
+	if !ok {
+		return span, nil
+	}
+	appendStringTag(span, tenantIDKey, tenant)
+	appendStringTag(span, tenantInferredTag, tenant)
+	return span, nil
+}
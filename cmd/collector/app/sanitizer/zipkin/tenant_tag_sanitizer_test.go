@@ -0,0 +1,62 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTenantTagSanitizer(t *testing.T) {
+	sanitizer := NewTenantTagSanitizer(func(span *zc.Span) (string, bool) {
+		if binAnno := findBinaryAnnotation(span, "http.header.x-tenant"); binAnno != nil {
+			return string(binAnno.Value), true
+		}
+		return "", false
+	})
+
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("http.header.x-tenant", "acme")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("http.header.x-tenant", "acme"),
+		stringTag(tenantIDKey, "acme"),
+		stringTag(tenantInferredTag, "acme"),
+	}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("http.header.x-tenant", "acme"),
+			stringTag(tenantIDKey, "globex"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 2)
+
+	span, err = sanitizer.Sanitize(&zc.Span{})
+	require.NoError(t, err)
+	assert.Empty(t, span.BinaryAnnotations)
+}
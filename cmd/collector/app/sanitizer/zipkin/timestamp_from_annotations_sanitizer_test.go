@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTimestampFromAnnotationsSanitizer(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+	tests := []struct {
+		timestamp         *int64
+		duration          *int64
+		annotations       []int64
+		expectedTimestamp *int64
+		expectedDuration  *int64
+		inferred          string
+		descr             string
+	}{
+		{int64Ptr(50), nil, []int64{100, 300}, int64Ptr(50), nil, "", "timestamp present"},
+		{nil, nil, []int64{300, 0, 100}, int64Ptr(100), int64Ptr(200), "100", "no timestamp nor duration"},
+		{nil, int64Ptr(500), []int64{100, 300}, int64Ptr(100), int64Ptr(500), "100", "no timestamp"},
+		{nil, nil, []int64{100, 100}, int64Ptr(100), int64Ptr(1), "100", "single instant"},
+		{nil, nil, nil, nil, nil, "", "no annotations"},
+		{nil, nil, []int64{0}, nil, nil, "", "no annotation timestamps"},
+	}
+	sanitizer := NewTimestampFromAnnotationsSanitizer()
+	for _, test := range tests {
+		span := &zc.Span{Timestamp: test.timestamp, Duration: test.duration}
+		for _, ts := range test.annotations {
+			span.Annotations = append(span.Annotations, &zc.Annotation{Timestamp: ts})
+		}
+		span, err := sanitizer.Sanitize(span)
+		require.NoError(t, err)
+		assert.Equal(t, test.expectedTimestamp, span.Timestamp, test.descr)
+		assert.Equal(t, test.expectedDuration, span.Duration, test.descr)
+		if test.inferred == "" {
+			assert.Empty(t, span.BinaryAnnotations, test.descr)
+		} else {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(inferredTimestampTag, test.inferred)}, span.BinaryAnnotations, test.descr)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/uber/jaeger/model"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const futureTimestampTag = "errFutureTimestamp"
+
+// NewTimestampSanitizer returns a sanitizer that deals with span timestamps in the future, e.g. because of
+// a misconfigured client clock. A timestamp more than maxSkew ahead of the current time is replaced with the
+// current time, and the original value is recorded in an 'errFutureTimestamp' tag. Nil timestamps are left
+// to the other sanitizers.
+func NewTimestampSanitizer(logger *zap.Logger, maxSkew time.Duration) Sanitizer {
+	return &timestampSanitizer{
+		log:     spanLogger{logger},
+		maxSkew: maxSkew,
+		timeNow: time.Now,
+	}
+}
+
+type timestampSanitizer struct {
+	log     spanLogger
+	maxSkew time.Duration
+	timeNow func() time.Time
+}
+
+func (s *timestampSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.Timestamp == nil {
+		return span, nil
+	}
+	now := s.timeNow()
+	if *span.Timestamp <= int64(model.TimeAsEpochMicroseconds(now.Add(s.maxSkew))) {
+		return span, nil
+	}
+	s.log.ForSpan(span).Debug("Future span timestamp", zap.Int64("timestamp", *span.Timestamp))
+	appendStringTag(span, futureTimestampTag, strconv.FormatInt(*span.Timestamp, 10))
+	timestamp := int64(model.TimeAsEpochMicroseconds(now))
+	span.Timestamp = &timestamp
+	return span, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTimestampSanitizer(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	sanitizer := NewTimestampSanitizer(zap.NewNop(), time.Minute).(*timestampSanitizer)
+	sanitizer.timeNow = func() time.Time {
+		return now
+	}
+	nowMicros := int64(1500000000000000)
+
+	span, err := sanitizer.Sanitize(&zc.Span{})
+	require.NoError(t, err)
+	assert.Nil(t, span.Timestamp)
+	assert.Empty(t, span.BinaryAnnotations)
+
+	tests := []struct {
+		timestamp int64
+		expected  int64
+		tagged    bool
+	}{
+		{timestamp: nowMicros, expected: nowMicros},
+		{timestamp: nowMicros + 60000000, expected: nowMicros + 60000000},
+		{timestamp: nowMicros + 60000001, expected: nowMicros, tagged: true},
+	}
+	for _, test := range tests {
+		timestamp := test.timestamp
+		span, err := sanitizer.Sanitize(&zc.Span{Timestamp: &timestamp})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, *span.Timestamp)
+		if test.tagged {
+			assert.Equal(t, []*zc.BinaryAnnotation{stringTag(futureTimestampTag, "1500000060000001")}, span.BinaryAnnotations)
+		} else {
+			assert.Empty(t, span.BinaryAnnotations)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const traceAttributeInconsistentTag = "warnTraceAttributeInconsistent"
+
+// NewTraceAttributeConsistencySanitizer returns a batch sanitizer that checks that the trace-level tags with
+// the given keys, e.g. 'deployment.environment', have the same value on all spans of a trace in the batch.
+// The spans of a trace with disagreeing values get a 'warnTraceAttributeInconsistent' tag listing the keys.
+// If propagate is true, the most common value of each key, the first seen on ties, is copied to the spans
+// of the trace missing it.
+func NewTraceAttributeConsistencySanitizer(keys []string, logger *zap.Logger, propagate bool) BatchSanitizer {
+	return &traceAttributeConsistencySanitizer{keys: keys, logger: logger, propagate: propagate}
+}
+
+type traceAttributeConsistencySanitizer struct {
+	keys      []string
+	logger    *zap.Logger
+	propagate bool
+}
+
+func (s *traceAttributeConsistencySanitizer) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	var traceIDs []int64
+	traces := make(map[int64][]*zc.Span)
+	for _, span := range spans {
+		if _, ok := traces[span.TraceID]; !ok {
+			traceIDs = append(traceIDs, span.TraceID)
+		}
+		traces[span.TraceID] = append(traces[span.TraceID], span)
+	}
+	for _, traceID := range traceIDs {
+		s.sanitizeTrace(traceID, traces[traceID])
+	}
+	return spans
+}
+
+func (s *traceAttributeConsistencySanitizer) sanitizeTrace(traceID int64, spans []*zc.Span) {
+	var inconsistent []string
+	for _, key := range s.keys {
+		var values []string
+		counts := make(map[string]int)
+		first := make(map[string]*zc.BinaryAnnotation)
+		var missing []*zc.Span
+		for _, span := range spans {
+			binAnno := findBinaryAnnotation(span, key)
+			if binAnno == nil {
+				missing = append(missing, span)
+				continue
+			}
+			value := binAnno.AnnotationType.String() + ":" + string(binAnno.Value)
+			if counts[value] == 0 {
+				values = append(values, value)
+				first[value] = binAnno
+			}
+			counts[value]++
+		}
+		if len(values) == 0 {
+			continue
+		}
+		majority := values[0]
+		for _, value := range values[1:] {
+			if counts[value] > counts[majority] {
+				majority = value
+			}
+		}
+		if len(values) > 1 {
+			inconsistent = append(inconsistent, key)
+			s.logger.Warn("Inconsistent trace attribute",
+				zap.String("traceID", strconv.FormatUint(uint64(traceID), 16)),
+				zap.String("key", key),
+				zap.Int("values", len(values)))
+		}
+		if !s.propagate {
+			continue
+		}
+		for _, span := range missing {
+			span.BinaryAnnotations = append(span.BinaryAnnotations, &zc.BinaryAnnotation{
+				Key:            key,
+				Value:          first[majority].Value,
+				AnnotationType: first[majority].AnnotationType,
+			})
+		}
+	}
+	if len(inconsistent) == 0 {
+		return
+	}
+	for _, span := range spans {
+		appendStringTag(span, traceAttributeInconsistentTag, strings.Join(inconsistent, ","))
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/jaeger/pkg/testutils"
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTraceAttributeConsistencySanitizer(t *testing.T) {
+	logger, logBuf := testutils.NewLogger()
+	sanitizer := NewTraceAttributeConsistencySanitizer([]string{"deployment.environment", "tenant.id"}, logger, true)
+	spans := sanitizer.SanitizeBatch([]*zc.Span{
+		{TraceID: 1, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("deployment.environment", "prod")}},
+		{TraceID: 2, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("tenant.id", "acme")}},
+		{TraceID: 1, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("deployment.environment", "prod")}},
+		{TraceID: 2},
+		{TraceID: 1, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("deployment.environment", "staging")}},
+		{TraceID: 1},
+	})
+	assert.Len(t, spans, 6)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("deployment.environment", "prod"),
+		stringTag(traceAttributeInconsistentTag, "deployment.environment"),
+	}, spans[0].BinaryAnnotations)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("deployment.environment", "staging"),
+		stringTag(traceAttributeInconsistentTag, "deployment.environment"),
+	}, spans[4].BinaryAnnotations)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("deployment.environment", "prod"),
+		stringTag(traceAttributeInconsistentTag, "deployment.environment"),
+	}, spans[5].BinaryAnnotations)
+
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag("tenant.id", "acme")}, spans[1].BinaryAnnotations)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag("tenant.id", "acme")}, spans[3].BinaryAnnotations)
+	assert.Contains(t, logBuf.String(), "Inconsistent trace attribute")
+}
+
+func TestTraceAttributeConsistencySanitizerNoPropagation(t *testing.T) {
+	logger, logBuf := testutils.NewLogger()
+	sanitizer := NewTraceAttributeConsistencySanitizer([]string{"tenant.id"}, logger, false)
+	spans := sanitizer.SanitizeBatch([]*zc.Span{
+		{TraceID: 1, BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("tenant.id", "acme")}},
+		{TraceID: 1},
+	})
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag("tenant.id", "acme")}, spans[0].BinaryAnnotations)
+	assert.Empty(t, spans[1].BinaryAnnotations)
+	assert.Empty(t, logBuf.String())
+}
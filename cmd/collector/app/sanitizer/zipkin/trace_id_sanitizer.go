@@ -0,0 +1,47 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"errors"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+// ErrZeroTraceID is returned by the trace ID sanitizer for spans with a zero trace ID.
+var ErrZeroTraceID = errors.New("span has a zero trace ID")
+
+// NewTraceIDSanitizer returns a sanitizer that rejects spans with a zero trace ID with ErrZeroTraceID, so that
+// the collector drops them instead of grouping unrelated spans into a phantom trace 0. Zipkin thrift spans only
+// carry the low 64 bits of the trace ID. See NewTraceIDValidationSanitizer for keeping such spans with a tag.
+func NewTraceIDSanitizer() Sanitizer {
+	return &traceIDSanitizer{}
+}
+
+type traceIDSanitizer struct {
+}
+
+func (s *traceIDSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	if span.TraceID == 0 {
+		return span, ErrZeroTraceID
+	}
+	return span, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTraceIDSanitizer(t *testing.T) {
+	tests := []struct {
+		traceID  int64
+		expected error
+	}{
+		{traceID: 0, expected: ErrZeroTraceID},
+		{traceID: 1},
+		{traceID: -1 << 63},
+	}
+	sanitizer := NewTraceIDSanitizer()
+	for _, test := range tests {
+		span := &zc.Span{TraceID: test.traceID}
+		actual, err := sanitizer.Sanitize(span)
+		assert.Equal(t, test.expected, err, "%x", test.traceID)
+		assert.True(t, span == actual)
+		assert.Empty(t, actual.BinaryAnnotations)
+	}
+}
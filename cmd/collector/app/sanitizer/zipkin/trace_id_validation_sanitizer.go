@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const zeroTraceIDTag = "errZeroTraceID"
+
+// NewTraceIDValidationSanitizer returns a batch sanitizer that guards against spans with a zero trace ID,
+// which cannot be correlated with any other span. Zipkin thrift spans only carry the low 64 bits of the trace ID.
+// If drop is set, such spans are dropped and counted per service, for up to maxServiceCounters services,
+// otherwise they are kept with an 'errZeroTraceID' tag.
+func NewTraceIDValidationSanitizer(drop bool, logger *zap.Logger, metricsFactory metrics.Factory) BatchSanitizer {
+	return &traceIDValidationSanitizer{
+		drop:    drop,
+		log:     spanLogger{logger},
+		dropped: newServiceCounters(metricsFactory, "spans.dropped", map[string]string{"reason": "zero-trace-id"}),
+	}
+}
+
+type traceIDValidationSanitizer struct {
+	drop    bool
+	log     spanLogger
+	dropped *serviceCounters
+}
+
+func (s *traceIDValidationSanitizer) SanitizeBatch(spans []*zc.Span) []*zc.Span {
+	return filterSpans(spans, s.keep)
+}
+
+func (s *traceIDValidationSanitizer) keep(span *zc.Span) bool {
+	if span.TraceID != 0 {
+		return true
+	}
+	if !s.drop {
+		if findBinaryAnnotation(span, zeroTraceIDTag) == nil {
+			appendStringTag(span, zeroTraceIDTag, "0")
+		}
+		return true
+	}
+	service := findServiceName(span)
+	s.log.ForSpan(span).Debug("Dropping span with zero trace ID", zap.String("service", service))
+	s.dropped.forService(service).Inc(1)
+	return false
+}
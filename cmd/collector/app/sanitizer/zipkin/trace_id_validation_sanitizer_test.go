@@ -0,0 +1,73 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func traceIDSpan(service string, traceID int64) *zc.Span {
+	return &zc.Span{
+		TraceID:     traceID,
+		Annotations: []*zc.Annotation{{Value: zc.SERVER_RECV, Host: &zc.Endpoint{ServiceName: service}}},
+	}
+}
+
+func TestTraceIDValidationSanitizerDrop(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	sanitizer := NewTraceIDValidationSanitizer(true, zap.NewNop(), metricsFactory)
+	valid := traceIDSpan("frontend", 42)
+	spans := sanitizer.SanitizeBatch([]*zc.Span{traceIDSpan("frontend", 0), valid})
+	assert.Equal(t, []*zc.Span{valid}, spans)
+	assert.Empty(t, valid.BinaryAnnotations)
+	counters, _ := metricsFactory.Snapshot()
+	assert.Equal(t, map[string]int64{"spans.dropped|reason=zero-trace-id|service=frontend": 1}, counters)
+}
+
+func TestTraceIDValidationSanitizerDropManyServices(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	sanitizer := NewTraceIDValidationSanitizer(true, zap.NewNop(), metricsFactory)
+	sanitizer.(*traceIDValidationSanitizer).dropped.maxServices = 1
+	spans := sanitizer.SanitizeBatch([]*zc.Span{traceIDSpan("frontend", 0), traceIDSpan("backend", 0), traceIDSpan("mysql", 0)})
+	assert.Empty(t, spans)
+	counters, _ := metricsFactory.Snapshot()
+	assert.Equal(t, map[string]int64{
+		"spans.dropped|reason=zero-trace-id|service=frontend":       1,
+		"spans.dropped|reason=zero-trace-id|service=other-services": 2,
+	}, counters)
+}
+
+func TestTraceIDValidationSanitizerTag(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	sanitizer := NewTraceIDValidationSanitizer(false, zap.NewNop(), metricsFactory)
+	spans := sanitizer.SanitizeBatch([]*zc.Span{traceIDSpan("frontend", 0), traceIDSpan("frontend", 42)})
+	assert.Len(t, spans, 2)
+	assert.Equal(t, []*zc.BinaryAnnotation{stringTag(zeroTraceIDTag, "0")}, spans[0].BinaryAnnotations)
+	assert.Empty(t, spans[1].BinaryAnnotations)
+	counters, _ := metricsFactory.Snapshot()
+	assert.Empty(t, counters)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const typeConflictTag = "warnTypeConflict"
+
+// TypeConflictPolicy defines how the type conflict sanitizer resolves binary annotations that share a key
+// but have different types.
+type TypeConflictPolicy int
+
+const (
+	// PreferNumericType keeps the annotations of the first numeric type and drops the others. If none of the
+	// annotations is numeric, the annotations of the first type are kept.
+	PreferNumericType TypeConflictPolicy = iota
+	// PreferFirstType keeps the annotations of the first type and drops the others.
+	PreferFirstType
+	// RenameConflictingType keeps the annotations of the first non-string type, and turns the others into
+	// STRING annotations with a '.str' suffix appended to their key.
+	RenameConflictingType
+)
+
+// NewTypeConflictSanitizer returns a sanitizer that resolves binary annotations sharing a key with different
+// types, e.g. a STRING and an I64, which schema-on-write backends reject. Conflicts are resolved according to
+// the policy, and the conflicting keys are recorded in a 'warnTypeConflict' tag.
+func NewTypeConflictSanitizer(policy TypeConflictPolicy, logger *zap.Logger) Sanitizer {
+	return &typeConflictSanitizer{policy: policy, log: spanLogger{logger}}
+}
+
+type typeConflictSanitizer struct {
+	policy TypeConflictPolicy
+	log    spanLogger
+}
+
+func (s *typeConflictSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var keys []string
+	types := make(map[string][]zc.AnnotationType)
+	for _, binAnno := range span.BinaryAnnotations {
+		seen := types[binAnno.Key]
+		if len(seen) == 0 {
+			keys = append(keys, binAnno.Key)
+		}
+		if !containsAnnotationType(seen, binAnno.AnnotationType) {
+			types[binAnno.Key] = append(seen, binAnno.AnnotationType)
+		}
+	}
+	var conflicts []string
+	kept := make(map[string]zc.AnnotationType)
+	for _, key := range keys {
+		if len(types[key]) > 1 {
+			conflicts = append(conflicts, key)
+			kept[key] = s.keptType(types[key])
+		}
+	}
+	if len(conflicts) == 0 {
+		return span, nil
+	}
+	binAnnos := make([]*zc.BinaryAnnotation, 0, len(span.BinaryAnnotations))
+	for _, binAnno := range span.BinaryAnnotations {
+		keptType, conflicting := kept[binAnno.Key]
+		if !conflicting || binAnno.AnnotationType == keptType {
+			binAnnos = append(binAnnos, binAnno)
+			continue
+		}
+		if s.policy != RenameConflictingType {
+			continue
+		}
+		if value, ok := valueString(binAnno); ok {
+			binAnno.Key += searchStringSuffix
+			binAnno.AnnotationType = zc.AnnotationType_STRING
+			binAnno.Value = []byte(value)
+			binAnnos = append(binAnnos, binAnno)
+		}
+	}
+	span.BinaryAnnotations = binAnnos
+	s.log.ForSpan(span).Debug("Resolved binary annotation type conflicts", zap.Strings("keys", conflicts))
+	appendStringTag(span, typeConflictTag, strings.Join(conflicts, ","))
+	return span, nil
+}
+
+// keptType returns the type of the annotations kept by the policy, given the types of a key in span order.
+func (s *typeConflictSanitizer) keptType(types []zc.AnnotationType) zc.AnnotationType {
+	for _, t := range types {
+		switch s.policy {
+		case PreferNumericType:
+			if isNumericAnnotationType(t) {
+				return t
+			}
+		case RenameConflictingType:
+			if t != zc.AnnotationType_STRING {
+				return t
+			}
+		}
+	}
+	return types[0]
+}
+
+func containsAnnotationType(types []zc.AnnotationType, t zc.AnnotationType) bool {
+	for _, other := range types {
+		if other == t {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumericAnnotationType(t zc.AnnotationType) bool {
+	switch t {
+	case zc.AnnotationType_I16, zc.AnnotationType_I32, zc.AnnotationType_I64, zc.AnnotationType_DOUBLE:
+		return true
+	}
+	return false
+}
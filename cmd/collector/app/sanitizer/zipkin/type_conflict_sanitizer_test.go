@@ -0,0 +1,87 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestTypeConflictSanitizer(t *testing.T) {
+	int64Tag := func(key string, value int64) *zc.BinaryAnnotation {
+		return &zc.BinaryAnnotation{Key: key, Value: int64Bytes(value), AnnotationType: zc.AnnotationType_I64}
+	}
+	tests := []struct {
+		policy   TypeConflictPolicy
+		expected []*zc.BinaryAnnotation
+	}{
+		{
+			policy: PreferNumericType,
+			expected: []*zc.BinaryAnnotation{
+				int64Tag("retries", 3),
+				stringTag("component", "http"),
+				stringTag(typeConflictTag, "retries"),
+			},
+		},
+		{
+			policy: PreferFirstType,
+			expected: []*zc.BinaryAnnotation{
+				stringTag("retries", "three"),
+				stringTag("component", "http"),
+				stringTag(typeConflictTag, "retries"),
+			},
+		},
+		{
+			policy: RenameConflictingType,
+			expected: []*zc.BinaryAnnotation{
+				stringTag("retries.str", "three"),
+				int64Tag("retries", 3),
+				stringTag("component", "http"),
+				stringTag(typeConflictTag, "retries"),
+			},
+		},
+	}
+	for _, test := range tests {
+		sanitizer := NewTypeConflictSanitizer(test.policy, zap.NewNop())
+		span, err := sanitizer.Sanitize(&zc.Span{
+			BinaryAnnotations: []*zc.BinaryAnnotation{
+				stringTag("retries", "three"),
+				int64Tag("retries", 3),
+				stringTag("component", "http"),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, span.BinaryAnnotations)
+	}
+}
+
+func TestTypeConflictSanitizerNoConflict(t *testing.T) {
+	span, err := NewTypeConflictSanitizer(PreferNumericType, zap.NewNop()).Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("component", "http"), stringTag("component", "grpc")},
+	})
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 2)
+}
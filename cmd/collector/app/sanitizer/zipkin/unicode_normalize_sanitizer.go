@@ -0,0 +1,59 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const unicodeNormalizedTag = "warnUnicodeNormalized"
+
+// NewUnicodeNormalizeSanitizer returns a sanitizer that normalizes the values of STRING tags to the given
+// Unicode normalization form, so that the same logical string is stored identically whatever the client.
+// The zero value of norm.Form is NFC. Values that are not valid UTF-8 are left untouched. The keys of
+// the normalized tags are recorded in a 'warnUnicodeNormalized' tag.
+func NewUnicodeNormalizeSanitizer(form norm.Form) Sanitizer {
+	return &unicodeNormalizeSanitizer{form: form}
+}
+
+type unicodeNormalizeSanitizer struct {
+	form norm.Form
+}
+
+func (s *unicodeNormalizeSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var normalized []string
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.AnnotationType != zc.AnnotationType_STRING || !utf8.Valid(binAnno.Value) || s.form.IsNormal(binAnno.Value) {
+			continue
+		}
+		binAnno.Value = s.form.Bytes(binAnno.Value)
+		normalized = append(normalized, binAnno.Key)
+	}
+	if len(normalized) > 0 {
+		appendStringTag(span, unicodeNormalizedTag, strings.Join(normalized, ","))
+	}
+	return span, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	zurichNFC = "Z\u00fcrich"
+	zurichNFD = "Zu\u0308rich"
+)
+
+func TestUnicodeNormalizeSanitizer(t *testing.T) {
+	sanitizer := NewUnicodeNormalizeSanitizer(norm.NFC)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("city", zurichNFD),
+			stringTag("country", "Schweiz"),
+			stringTag("invalid", zurichNFD+"\xff"),
+			{Key: "bytes", Value: []byte(zurichNFD), AnnotationType: zc.AnnotationType_BYTES},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("city", zurichNFC),
+		stringTag("country", "Schweiz"),
+		stringTag("invalid", zurichNFD+"\xff"),
+		{Key: "bytes", Value: []byte(zurichNFD), AnnotationType: zc.AnnotationType_BYTES},
+		stringTag(unicodeNormalizedTag, "city"),
+	}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(span)
+	require.NoError(t, err)
+	assert.Len(t, span.BinaryAnnotations, 5, "normalization must be idempotent")
+}
+
+func TestUnicodeNormalizeSanitizerNFD(t *testing.T) {
+	sanitizer := NewUnicodeNormalizeSanitizer(norm.NFD)
+	span, err := sanitizer.Sanitize(&zc.Span{
+		BinaryAnnotations: []*zc.BinaryAnnotation{stringTag("city", zurichNFC)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, stringTag("city", zurichNFD), span.BinaryAnnotations[0])
+}
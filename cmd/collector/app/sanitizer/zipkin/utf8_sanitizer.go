@@ -0,0 +1,90 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"bytes"
+	"strconv"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const invalidUTF8Tag = "errInvalidUTF8"
+
+// NewUTF8Sanitizer returns a sanitizer that repairs invalid UTF-8 in the span name and in the values of
+// STRING binary annotations, which storage backends such as Cassandra reject. Each run of invalid bytes is
+// replaced with the Unicode replacement character, and the original byte length of each repaired value is
+// recorded in an 'errInvalidUTF8' tag.
+func NewUTF8Sanitizer(logger *zap.Logger) Sanitizer {
+	return &utf8Sanitizer{log: spanLogger{logger}}
+}
+
+type utf8Sanitizer struct {
+	log spanLogger
+}
+
+func (s *utf8Sanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var lengths []int
+	if !utf8.ValidString(span.Name) {
+		lengths = append(lengths, len(span.Name))
+		span.Name = string(toValidUTF8([]byte(span.Name)))
+	}
+	for _, binAnno := range span.BinaryAnnotations {
+		if binAnno.AnnotationType != zc.AnnotationType_STRING || utf8.Valid(binAnno.Value) {
+			continue
+		}
+		lengths = append(lengths, len(binAnno.Value))
+		binAnno.Value = toValidUTF8(binAnno.Value)
+	}
+	if len(lengths) == 0 {
+		return span, nil
+	}
+	s.log.ForSpan(span).Debug("Repaired invalid UTF-8", zap.Int("values", len(lengths)))
+	for _, length := range lengths {
+		appendStringTag(span, invalidUTF8Tag, strconv.Itoa(length))
+	}
+	return span, nil
+}
+
+// toValidUTF8 returns a copy of the value with each run of invalid UTF-8 bytes replaced
+// with the Unicode replacement character.
+func toValidUTF8(value []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(value))
+	invalid := false
+	for len(value) > 0 {
+		r, size := utf8.DecodeRune(value)
+		if r == utf8.RuneError && size == 1 {
+			if !invalid {
+				buf.WriteRune(utf8.RuneError)
+				invalid = true
+			}
+		} else {
+			buf.Write(value[:size])
+			invalid = false
+		}
+		value = value[size:]
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestUTF8Sanitizer(t *testing.T) {
+	sanitizer := NewUTF8Sanitizer(zap.NewNop())
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Name: "get \xe6\x97",
+		BinaryAnnotations: []*zc.BinaryAnnotation{
+			stringTag("city", "Zürich 日本"),
+			stringTag("payload", "ab\xff\xfecd\xe2\x82"),
+			{Key: "raw", Value: []byte{0xff}, AnnotationType: zc.AnnotationType_BYTES},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "get �", span.Name)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		stringTag("city", "Zürich 日本"),
+		stringTag("payload", "ab�cd�"),
+		{Key: "raw", Value: []byte{0xff}, AnnotationType: zc.AnnotationType_BYTES},
+		stringTag(invalidUTF8Tag, "6"),
+		stringTag(invalidUTF8Tag, "8"),
+	}, span.BinaryAnnotations)
+
+	span, err = sanitizer.Sanitize(&zc.Span{Name: "日本"})
+	require.NoError(t, err)
+	assert.Equal(t, "日本", span.Name)
+	assert.Empty(t, span.BinaryAnnotations)
+}
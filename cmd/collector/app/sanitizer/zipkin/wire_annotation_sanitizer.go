@@ -0,0 +1,123 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"strconv"
+	"strings"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+const (
+	wireAnnotationParsedTag = "warnWireAnnotationParsed"
+	messageSentSizeKey      = "message.sent.size"
+	messageReceivedSizeKey  = "message.received.size"
+	messageSentLatencyKey   = "message.sent.latency_us"
+	messageRecvLatencyKey   = "message.received.latency_us"
+)
+
+// NewWireAnnotationSanitizer returns a sanitizer that extracts message sizes and timings
+// from wire send ('ws') and wire receive ('wr') annotations.
+//
+// The annotation value is expected to be either the bare 'ws'/'wr' or to carry the message size
+// in bytes after a colon, e.g. 'ws:1024'. The size is recorded in the 'message.sent.size' and
+// 'message.received.size' I64 tags. The time between the send core annotation (cs or ss) and 'ws',
+// and between 'wr' and the receive core annotation (cr or sr), is recorded in microseconds in the
+// 'message.sent.latency_us' and 'message.received.latency_us' I64 tags.
+func NewWireAnnotationSanitizer() Sanitizer {
+	return &wireAnnotationSanitizer{}
+}
+
+type wireAnnotationSanitizer struct {
+}
+
+func (s *wireAnnotationSanitizer) Sanitize(span *zc.Span) (*zc.Span, error) {
+	var (
+		wireSend, wireRecv *zc.Annotation
+		send, recv         *zc.Annotation
+	)
+	for _, anno := range span.Annotations {
+		switch wireAnnotationKind(anno.Value) {
+		case zc.WIRE_SEND:
+			if wireSend == nil {
+				wireSend = anno
+			}
+		case zc.WIRE_RECV:
+			if wireRecv == nil {
+				wireRecv = anno
+			}
+		}
+		if send == nil && (anno.Value == zc.CLIENT_SEND || anno.Value == zc.SERVER_SEND) {
+			send = anno
+		}
+		if recv == nil && (anno.Value == zc.CLIENT_RECV || anno.Value == zc.SERVER_RECV) {
+			recv = anno
+		}
+	}
+	var parsed []string
+	if wireSend != nil {
+		if size, ok := wireAnnotationSize(wireSend.Value); ok {
+			appendInt64Tag(span, messageSentSizeKey, size)
+		}
+		if send != nil {
+			appendInt64Tag(span, messageSentLatencyKey, wireSend.Timestamp-send.Timestamp)
+		}
+		parsed = append(parsed, zc.WIRE_SEND)
+	}
+	if wireRecv != nil {
+		if size, ok := wireAnnotationSize(wireRecv.Value); ok {
+			appendInt64Tag(span, messageReceivedSizeKey, size)
+		}
+		if recv != nil {
+			appendInt64Tag(span, messageRecvLatencyKey, recv.Timestamp-wireRecv.Timestamp)
+		}
+		parsed = append(parsed, zc.WIRE_RECV)
+	}
+	if len(parsed) > 0 {
+		appendStringTag(span, wireAnnotationParsedTag, strings.Join(parsed, ","))
+	}
+	return span, nil
+}
+
+// wireAnnotationKind returns 'ws' or 'wr' for wire annotations, and "" for any other annotation.
+func wireAnnotationKind(value string) string {
+	if i := strings.IndexByte(value, ':'); i >= 0 {
+		value = value[:i]
+	}
+	if value == zc.WIRE_SEND || value == zc.WIRE_RECV {
+		return value
+	}
+	return ""
+}
+
+// wireAnnotationSize parses the message size out of a 'ws:<size>' or 'wr:<size>' annotation value.
+func wireAnnotationSize(value string) (int64, bool) {
+	i := strings.IndexByte(value, ':')
+	if i < 0 {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(value[i+1:]), 10, 64)
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
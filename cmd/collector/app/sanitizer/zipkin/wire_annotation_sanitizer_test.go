@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zc "github.com/uber/jaeger/thrift-gen/zipkincore"
+)
+
+func TestWireAnnotationSanitizer(t *testing.T) {
+	sanitizer := NewWireAnnotationSanitizer()
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: zc.CLIENT_SEND, Timestamp: 100},
+			{Value: "ws:1024", Timestamp: 110},
+			{Value: "wr:2048", Timestamp: 180},
+			{Value: zc.CLIENT_RECV, Timestamp: 200},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		{Key: messageSentSizeKey, Value: int64Bytes(1024), AnnotationType: zc.AnnotationType_I64},
+		{Key: messageSentLatencyKey, Value: int64Bytes(10), AnnotationType: zc.AnnotationType_I64},
+		{Key: messageReceivedSizeKey, Value: int64Bytes(2048), AnnotationType: zc.AnnotationType_I64},
+		{Key: messageRecvLatencyKey, Value: int64Bytes(20), AnnotationType: zc.AnnotationType_I64},
+		stringTag(wireAnnotationParsedTag, "ws,wr"),
+	}, span.BinaryAnnotations)
+}
+
+func TestWireAnnotationSanitizerWithoutSize(t *testing.T) {
+	sanitizer := NewWireAnnotationSanitizer()
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{
+			{Value: "wr", Timestamp: 100},
+			{Value: zc.SERVER_RECV, Timestamp: 130},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*zc.BinaryAnnotation{
+		{Key: messageRecvLatencyKey, Value: int64Bytes(30), AnnotationType: zc.AnnotationType_I64},
+		stringTag(wireAnnotationParsedTag, "wr"),
+	}, span.BinaryAnnotations)
+}
+
+func TestWireAnnotationSanitizerNoWireAnnotations(t *testing.T) {
+	sanitizer := NewWireAnnotationSanitizer()
+	span, err := sanitizer.Sanitize(&zc.Span{
+		Annotations: []*zc.Annotation{{Value: zc.CLIENT_SEND}, {Value: "wsx:10"}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, span.BinaryAnnotations)
+}
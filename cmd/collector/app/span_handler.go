@@ -21,6 +21,9 @@
 package app
 
 import (
+	"strconv"
+
+	"github.com/uber/jaeger-lib/metrics"
 	"github.com/uber/tchannel-go/thrift"
 	"go.uber.org/zap"
 
@@ -104,32 +107,54 @@ type zipkinSpanHandler struct {
 	logger         *zap.Logger
 	sanitizer      zipkinS.Sanitizer
 	modelProcessor SpanProcessor
+	// spansDropped counts the spans that were not processed because the sanitizer returned an error
+	spansDropped metrics.Counter
 }
 
 // NewZipkinSpanHandler returns a ZipkinSpansHandler
-func NewZipkinSpanHandler(logger *zap.Logger, modelHandler SpanProcessor, sanitizer zipkinS.Sanitizer) ZipkinSpansHandler {
+func NewZipkinSpanHandler(
+	logger *zap.Logger,
+	modelHandler SpanProcessor,
+	sanitizer zipkinS.Sanitizer,
+	metricsFactory metrics.Factory,
+) ZipkinSpansHandler {
 	return &zipkinSpanHandler{
 		logger:         logger,
 		modelProcessor: modelHandler,
 		sanitizer:      sanitizer,
+		spansDropped:   metricsFactory.Counter("zipkin.spans.sanitizer-dropped", nil),
 	}
 }
 
 // SubmitZipkinBatch records a batch of spans already in Zipkin Thrift format.
 func (h *zipkinSpanHandler) SubmitZipkinBatch(ctx thrift.Context, spans []*zipkincore.Span) ([]*zipkincore.Response, error) {
-	mSpans := make([]*model.Span, len(spans))
+	mSpans := make([]*model.Span, 0, len(spans))
+	dropped := make([]bool, len(spans))
 	for i, span := range spans {
-		sanitized := h.sanitizer.Sanitize(span)
-		mSpans[i] = ConvertZipkinToModel(sanitized, h.logger)
+		sanitized, err := h.sanitizer.Sanitize(span)
+		if err != nil {
+			h.logger.Warn("Dropping zipkin span that cannot be sanitized",
+				zap.String("traceID", strconv.FormatUint(uint64(span.TraceID), 16)),
+				zap.String("spanID", strconv.FormatUint(uint64(span.ID), 16)),
+				zap.Error(err))
+			dropped[i] = true
+			h.spansDropped.Inc(1)
+			continue
+		}
+		mSpans = append(mSpans, ConvertZipkinToModel(sanitized, h.logger))
 	}
 	bools, err := h.modelProcessor.ProcessSpans(mSpans, ZipkinFormatType)
 	if err != nil {
 		return nil, err
 	}
 	responses := make([]*zipkincore.Response, len(spans))
-	for i, ok := range bools {
+	processed := 0
+	for i := range spans {
 		res := zipkincore.NewResponse()
-		res.Ok = ok
+		if !dropped[i] {
+			res.Ok = bools[processed]
+			processed++
+		}
 		responses[i] = res
 	}
 	return responses, nil
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-lib/metrics"
 	"github.com/uber/tchannel-go/thrift"
 	"go.uber.org/zap"
 
@@ -98,7 +99,7 @@ func TestZipkinSpanHandler(t *testing.T) {
 	}
 	for _, tc := range testChunks {
 		logger := zap.NewNop()
-		h := NewZipkinSpanHandler(logger, &shouldIErrorProcessor{tc.expectedErr != nil}, zipkin.NewParentIDSanitizer(logger))
+		h := NewZipkinSpanHandler(logger, &shouldIErrorProcessor{tc.expectedErr != nil}, zipkin.NewParentIDSanitizer(logger), metrics.NullFactory)
 		ctx, cancel := thrift.NewContext(time.Minute)
 		defer cancel()
 		res, err := h.SubmitZipkinBatch(ctx, []*zipkincore.Span{
@@ -116,3 +117,34 @@ func TestZipkinSpanHandler(t *testing.T) {
 		}
 	}
 }
+
+type unrecoverableSanitizer struct{}
+
+var errUnrecoverableSpan = errors.New("unrecoverable span")
+
+func (s unrecoverableSanitizer) Sanitize(span *zipkincore.Span) (*zipkincore.Span, error) {
+	if span.ID == 0 {
+		return span, errUnrecoverableSpan
+	}
+	if span.ID == 2 {
+		return span, zipkin.ErrDropSpan
+	}
+	return span, nil
+}
+
+func TestZipkinSpanHandlerDropsUnsanitizableSpans(t *testing.T) {
+	metricsFactory := metrics.NewLocalFactory(0)
+	h := NewZipkinSpanHandler(zap.NewNop(), &shouldIErrorProcessor{false}, unrecoverableSanitizer{}, metricsFactory)
+	ctx, cancel := thrift.NewContext(time.Minute)
+	defer cancel()
+	res, err := h.SubmitZipkinBatch(ctx, []*zipkincore.Span{{ID: 1}, {ID: 0}, {ID: 3}, {ID: 2}})
+	assert.NoError(t, err)
+	if assert.Len(t, res, 4) {
+		assert.True(t, res[0].Ok)
+		assert.False(t, res[1].Ok)
+		assert.True(t, res[2].Ok)
+		assert.False(t, res[3].Ok)
+	}
+	counters, _ := metricsFactory.Snapshot()
+	assert.EqualValues(t, 2, counters["zipkin.spans.sanitizer-dropped"])
+}
@@ -86,6 +86,12 @@ func ToDomainSpan(zSpan *zipkincore.Span) (*model.Span, error) {
 	return toDomain{}.ToDomainSpan(zSpan)
 }
 
+// ToDomainTag transforms a binary annotation in zipkin.thrift format into a model.KeyValue, as done for
+// the tags of spans transformed by ToDomainSpan. An error is returned if the value cannot be decoded.
+func ToDomainTag(binaryAnnotation *zipkincore.BinaryAnnotation) (model.KeyValue, error) {
+	return toDomain{}.transformBinaryAnnotation(binaryAnnotation)
+}
+
 type toDomain struct{}
 
 func (td toDomain) ToDomain(zSpans []*zipkincore.Span) (*model.Trace, error) {
@@ -87,6 +87,15 @@ func TestInvalidAnnotationTypeError(t *testing.T) {
 	assert.EqualError(t, err, "Unknown zipkin annotation type: -1")
 }
 
+func TestToDomainTag(t *testing.T) {
+	kv, err := ToDomainTag(&z.BinaryAnnotation{Key: "count", Value: []byte{0, 0, 0, 7}, AnnotationType: z.AnnotationType_I32})
+	require.NoError(t, err)
+	assert.Equal(t, model.Int64("count", 7), kv)
+
+	_, err = ToDomainTag(&z.BinaryAnnotation{Key: "count", Value: []byte{0, 7}, AnnotationType: z.AnnotationType_I32})
+	assert.Error(t, err)
+}
+
 // TestZipkinEncoding is just for reference to explain the base64 strings
 // used in zipkin_03.json and jaeger_03.json fixtures
 func TestValidateBase64Values(t *testing.T) {